@@ -15,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	agentpkg "github.com/leneffets/awsserver/pkg/agent"
 	ecrpkg "github.com/leneffets/awsserver/pkg/ecr"
 	s3pkg "github.com/leneffets/awsserver/pkg/s3"
 	smpkg "github.com/leneffets/awsserver/pkg/secretsmanager"
@@ -32,6 +33,11 @@ func (r *statusRecorder) WriteHeader(code int) {
 	r.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying connection.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -61,6 +67,16 @@ func main() {
 	stsSvc := sts.NewFromConfig(cfg)
 	smSvc := secretsmanager.NewFromConfig(cfg)
 
+	agentTTL := 5 * time.Minute
+	if v := os.Getenv("SECRETS_AGENT_TTL"); v != "" {
+		agentTTL, err = time.ParseDuration(v)
+		if err != nil {
+			slog.Error("invalid SECRETS_AGENT_TTL", "value", v, "error", err)
+			os.Exit(1)
+		}
+	}
+	agent := agentpkg.New(smSvc, ssmSvc, os.Getenv("SECRETS_AGENT_TOKEN"), agentTTL)
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
@@ -72,10 +88,46 @@ func main() {
 		ssmpkg.HandleSSM(w, r, ssmSvc)
 	})
 
+	mux.HandleFunc("/ssm/path", func(w http.ResponseWriter, r *http.Request) {
+		ssmpkg.HandleSSMPath(w, r, ssmSvc)
+	})
+
+	mux.HandleFunc("/ssm/batch", func(w http.ResponseWriter, r *http.Request) {
+		ssmpkg.HandleSSMBatch(w, r, ssmSvc)
+	})
+
+	mux.HandleFunc("/ssm/history", func(w http.ResponseWriter, r *http.Request) {
+		ssmpkg.HandleSSMHistory(w, r, ssmSvc)
+	})
+
+	mux.HandleFunc("/ssm/labels", func(w http.ResponseWriter, r *http.Request) {
+		ssmpkg.HandleSSMLabels(w, r, ssmSvc)
+	})
+
+	mux.HandleFunc("/ssm/export", func(w http.ResponseWriter, r *http.Request) {
+		ssmpkg.HandleSSMExport(w, r, ssmSvc)
+	})
+
+	mux.HandleFunc("/ssm/describe", func(w http.ResponseWriter, r *http.Request) {
+		ssmpkg.HandleSSMDescribe(w, r, ssmSvc)
+	})
+
 	mux.HandleFunc("/s3", func(w http.ResponseWriter, r *http.Request) {
 		s3pkg.HandleS3(w, r, s3Svc)
 	})
 
+	mux.HandleFunc("/s3/list", func(w http.ResponseWriter, r *http.Request) {
+		s3pkg.HandleS3List(w, r, s3Svc)
+	})
+
+	mux.HandleFunc("/s3/multipart", func(w http.ResponseWriter, r *http.Request) {
+		s3pkg.HandleS3Multipart(w, r, s3Svc)
+	})
+
+	mux.HandleFunc("/s3/multipart/complete", func(w http.ResponseWriter, r *http.Request) {
+		s3pkg.HandleS3MultipartComplete(w, r, s3Svc)
+	})
+
 	mux.HandleFunc("/ecr/login", func(w http.ResponseWriter, r *http.Request) {
 		ecrpkg.HandleECRLogin(w, r, ecrSvc)
 	})
@@ -88,6 +140,33 @@ func main() {
 		smpkg.HandleSecrets(w, r, smSvc)
 	})
 
+	mux.HandleFunc("/secrets/restore", func(w http.ResponseWriter, r *http.Request) {
+		smpkg.HandleSecretRestore(w, r, smSvc)
+	})
+
+	mux.HandleFunc("/secrets/list", func(w http.ResponseWriter, r *http.Request) {
+		smpkg.HandleSecretsList(w, r, smSvc)
+	})
+
+	mux.HandleFunc("/secrets/describe", func(w http.ResponseWriter, r *http.Request) {
+		smpkg.HandleSecretsDescribe(w, r, smSvc)
+	})
+
+	mux.HandleFunc("/secrets/rotate", func(w http.ResponseWriter, r *http.Request) {
+		smpkg.HandleSecretRotate(w, r, smSvc)
+	})
+
+	mux.HandleFunc("/secrets/cancel-rotation", func(w http.ResponseWriter, r *http.Request) {
+		smpkg.HandleSecretCancelRotation(w, r, smSvc)
+	})
+
+	mux.HandleFunc("/secrets/random-password", func(w http.ResponseWriter, r *http.Request) {
+		smpkg.HandleRandomPassword(w, r, smSvc)
+	})
+
+	mux.HandleFunc("/secretsmanager/get", agent.HandleSecret)
+	mux.HandleFunc("/systems-manager/parameters/get", agent.HandleParameter)
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "3000"
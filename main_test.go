@@ -43,6 +43,38 @@ func (m *MockSSMAPI) PutParameter(ctx context.Context, params *ssm.PutParameterI
 	return &m.PutResp, m.Err
 }
 
+func (m *MockSSMAPI) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	return &ssm.GetParametersByPathOutput{}, m.Err
+}
+
+func (m *MockSSMAPI) GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	return &ssm.GetParametersOutput{}, m.Err
+}
+
+func (m *MockSSMAPI) GetParameterHistory(ctx context.Context, params *ssm.GetParameterHistoryInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error) {
+	return &ssm.GetParameterHistoryOutput{}, m.Err
+}
+
+func (m *MockSSMAPI) DeleteParameter(ctx context.Context, params *ssm.DeleteParameterInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParameterOutput, error) {
+	return &ssm.DeleteParameterOutput{}, m.Err
+}
+
+func (m *MockSSMAPI) DeleteParameters(ctx context.Context, params *ssm.DeleteParametersInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParametersOutput, error) {
+	return &ssm.DeleteParametersOutput{}, m.Err
+}
+
+func (m *MockSSMAPI) LabelParameterVersion(ctx context.Context, params *ssm.LabelParameterVersionInput, optFns ...func(*ssm.Options)) (*ssm.LabelParameterVersionOutput, error) {
+	return &ssm.LabelParameterVersionOutput{}, m.Err
+}
+
+func (m *MockSSMAPI) UnlabelParameterVersion(ctx context.Context, params *ssm.UnlabelParameterVersionInput, optFns ...func(*ssm.Options)) (*ssm.UnlabelParameterVersionOutput, error) {
+	return &ssm.UnlabelParameterVersionOutput{}, m.Err
+}
+
+func (m *MockSSMAPI) DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error) {
+	return &ssm.DescribeParametersOutput{}, m.Err
+}
+
 // Mock S3
 type MockS3API struct {
 	GetResp s3.GetObjectOutput
@@ -57,6 +89,46 @@ func (m *MockS3API) PutObject(ctx context.Context, params *s3.PutObjectInput, op
 	return &s3.PutObjectOutput{}, m.Err
 }
 
+func (m *MockS3API) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	return &s3.ListObjectsV2Output{}, m.Err
+}
+
+func (m *MockS3API) CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	return &s3.CreateMultipartUploadOutput{}, m.Err
+}
+
+func (m *MockS3API) UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	return &s3.UploadPartOutput{}, m.Err
+}
+
+func (m *MockS3API) CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	return &s3.CompleteMultipartUploadOutput{}, m.Err
+}
+
+func (m *MockS3API) AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	return &s3.AbortMultipartUploadOutput{}, m.Err
+}
+
+func (m *MockS3API) ListParts(ctx context.Context, params *s3.ListPartsInput, optFns ...func(*s3.Options)) (*s3.ListPartsOutput, error) {
+	return &s3.ListPartsOutput{}, m.Err
+}
+
+func (m *MockS3API) ListMultipartUploads(ctx context.Context, params *s3.ListMultipartUploadsInput, optFns ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+	return &s3.ListMultipartUploadsOutput{}, m.Err
+}
+
+func (m *MockS3API) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	return &s3.HeadObjectOutput{}, m.Err
+}
+
+func (m *MockS3API) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	return &s3.DeleteObjectOutput{}, m.Err
+}
+
+func (m *MockS3API) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	return &s3.DeleteObjectsOutput{}, m.Err
+}
+
 // Mock ECR
 type MockECRAPI struct {
 	Resp ecr.GetAuthorizationTokenOutput
@@ -87,6 +159,50 @@ func (m *MockSMAPI) GetSecretValue(ctx context.Context, params *secretsmanager.G
 	return &m.Resp, m.Err
 }
 
+func (m *MockSMAPI) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	return &secretsmanager.CreateSecretOutput{}, m.Err
+}
+
+func (m *MockSMAPI) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	return &secretsmanager.PutSecretValueOutput{}, m.Err
+}
+
+func (m *MockSMAPI) UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error) {
+	return &secretsmanager.UpdateSecretOutput{}, m.Err
+}
+
+func (m *MockSMAPI) DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	return &secretsmanager.DeleteSecretOutput{}, m.Err
+}
+
+func (m *MockSMAPI) RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error) {
+	return &secretsmanager.RestoreSecretOutput{}, m.Err
+}
+
+func (m *MockSMAPI) ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	return &secretsmanager.ListSecretsOutput{}, m.Err
+}
+
+func (m *MockSMAPI) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	return &secretsmanager.DescribeSecretOutput{}, m.Err
+}
+
+func (m *MockSMAPI) BatchGetSecretValue(ctx context.Context, params *secretsmanager.BatchGetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.BatchGetSecretValueOutput, error) {
+	return &secretsmanager.BatchGetSecretValueOutput{}, m.Err
+}
+
+func (m *MockSMAPI) RotateSecret(ctx context.Context, params *secretsmanager.RotateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RotateSecretOutput, error) {
+	return &secretsmanager.RotateSecretOutput{}, m.Err
+}
+
+func (m *MockSMAPI) CancelRotateSecret(ctx context.Context, params *secretsmanager.CancelRotateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CancelRotateSecretOutput, error) {
+	return &secretsmanager.CancelRotateSecretOutput{}, m.Err
+}
+
+func (m *MockSMAPI) GetRandomPassword(ctx context.Context, params *secretsmanager.GetRandomPasswordInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetRandomPasswordOutput, error) {
+	return &secretsmanager.GetRandomPasswordOutput{}, m.Err
+}
+
 func TestHealthz(t *testing.T) {
 	req := httptest.NewRequest("GET", "/healthz", nil)
 	rr := httptest.NewRecorder()
@@ -272,7 +388,7 @@ func TestGetSecret(t *testing.T) {
 
 func TestMethodNotAllowed(t *testing.T) {
 	mock := &MockSSMAPI{}
-	req := httptest.NewRequest("DELETE", "/ssm", nil)
+	req := httptest.NewRequest("PATCH", "/ssm", nil)
 	rr := httptest.NewRecorder()
 	ssmpkg.HandleSSM(rr, req, mock)
 
@@ -0,0 +1,273 @@
+// Package agent implements the HTTP interface of the AWS Secrets Manager
+// Agent and the AWS Parameters and Secrets Lambda Extension, so code written
+// for those can run unmodified against this server.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	smpkg "github.com/leneffets/awsserver/pkg/secretsmanager"
+	ssmpkg "github.com/leneffets/awsserver/pkg/ssm"
+)
+
+// TokenHeader carries the token every request has to present.
+const TokenHeader = "X-Aws-Parameters-Secrets-Token"
+
+// maxCacheEntries bounds the number of cached responses.
+const maxCacheEntries = 1000
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// Agent serves cached secret and parameter lookups.
+type Agent struct {
+	sm    smpkg.SecretsManagerAPI
+	ssm   ssmpkg.SSMAPI
+	token string
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// New returns an Agent that caches responses for ttl; a zero ttl disables
+// caching. When token is empty any non-empty token header is accepted, which
+// still guards against request forgery by clients that cannot set headers.
+func New(sm smpkg.SecretsManagerAPI, ssm ssmpkg.SSMAPI, token string, ttl time.Duration) *Agent {
+	return &Agent{
+		sm:    sm,
+		ssm:   ssm,
+		token: token,
+		ttl:   ttl,
+		cache: make(map[string]cacheEntry),
+	}
+}
+
+type secretValueResponse struct {
+	ARN            string   `json:"ARN"`
+	CreatedDate    float64  `json:"CreatedDate"`
+	Name           string   `json:"Name"`
+	SecretBinary   []byte   `json:"SecretBinary,omitempty"`
+	SecretString   *string  `json:"SecretString,omitempty"`
+	VersionId      string   `json:"VersionId"`
+	VersionStages  []string `json:"VersionStages"`
+	ResultMetadata struct{} `json:"ResultMetadata"`
+}
+
+type parameter struct {
+	ARN              string  `json:"ARN"`
+	DataType         string  `json:"DataType"`
+	LastModifiedDate float64 `json:"LastModifiedDate"`
+	Name             string  `json:"Name"`
+	Selector         *string `json:"Selector"`
+	SourceResult     *string `json:"SourceResult"`
+	Type             string  `json:"Type"`
+	Value            string  `json:"Value"`
+	Version          int64   `json:"Version"`
+}
+
+type parameterResponse struct {
+	Parameter      parameter `json:"Parameter"`
+	ResultMetadata struct{}  `json:"ResultMetadata"`
+}
+
+// HandleSecret serves GET /secretsmanager/get?secretId= with optional
+// versionId and versionStage, answering with the GetSecretValue response.
+func (a *Agent) HandleSecret(w http.ResponseWriter, r *http.Request) {
+	if !a.authorize(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	id := query.Get("secretId")
+	if id == "" {
+		http.Error(w, "Parameter 'secretId' is required", http.StatusBadRequest)
+		return
+	}
+
+	a.serve(w, r, func(ctx context.Context) (any, error) {
+		out, err := smpkg.GetSecret(ctx, a.sm, id, query.Get("versionId"), query.Get("versionStage"))
+		if err != nil {
+			return nil, err
+		}
+		return secretValueResponse{
+			ARN:           aws.ToString(out.ARN),
+			CreatedDate:   epochSeconds(out.CreatedDate),
+			Name:          aws.ToString(out.Name),
+			SecretBinary:  out.SecretBinary,
+			SecretString:  out.SecretString,
+			VersionId:     aws.ToString(out.VersionId),
+			VersionStages: out.VersionStages,
+		}, nil
+	})
+}
+
+// HandleParameter serves GET /systems-manager/parameters/get?name= with
+// optional version or label and withDecryption, answering with the
+// GetParameter response.
+func (a *Agent) HandleParameter(w http.ResponseWriter, r *http.Request) {
+	if !a.authorize(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("name")
+	if name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+
+	name, err := ssmpkg.ParameterSelector(name, query.Get("version"), query.Get("label"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	decrypt := false
+	if v := query.Get("withDecryption"); v != "" {
+		if decrypt, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Parameter 'withDecryption' must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	a.serve(w, r, func(ctx context.Context) (any, error) {
+		out, err := a.ssm.GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(name),
+			WithDecryption: aws.Bool(decrypt),
+		})
+		if err != nil {
+			return nil, err
+		}
+		p := out.Parameter
+		return parameterResponse{Parameter: parameter{
+			ARN:              aws.ToString(p.ARN),
+			DataType:         aws.ToString(p.DataType),
+			LastModifiedDate: epochSeconds(p.LastModifiedDate),
+			Name:             aws.ToString(p.Name),
+			Selector:         p.Selector,
+			SourceResult:     p.SourceResult,
+			Type:             string(p.Type),
+			Value:            aws.ToString(p.Value),
+			Version:          p.Version,
+		}}, nil
+	})
+}
+
+func (a *Agent) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return false
+	}
+	token := r.Header.Get(TokenHeader)
+	if token == "" || (a.token != "" && token != a.token) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// serve answers from the cache when possible and otherwise calls fetch,
+// caching its JSON encoded result. 'refreshNow=true' bypasses the cache.
+func (a *Agent) serve(w http.ResponseWriter, r *http.Request, fetch func(ctx context.Context) (any, error)) {
+	query := r.URL.Query()
+	refresh := query.Get("refreshNow") == "true"
+	query.Del("refreshNow")
+	key := r.URL.Path + "?" + query.Encode()
+
+	if !refresh {
+		if body, ok := a.lookup(key); ok {
+			writeBody(w, body)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := fetch(ctx)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) && respErr.HTTPStatusCode() != 0 {
+			status = respErr.HTTPStatusCode()
+		}
+		slog.Error("agent lookup failed", "path", r.URL.Path, "error", err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		slog.Error("failed to encode response", "error", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+	a.store(key, body)
+	writeBody(w, body)
+}
+
+func (a *Agent) lookup(key string) ([]byte, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry, ok := a.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+func (a *Agent) store(key string, body []byte) {
+	if a.ttl <= 0 {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if len(a.cache) >= maxCacheEntries {
+		for k, e := range a.cache {
+			if now.After(e.expires) {
+				delete(a.cache, k)
+			}
+		}
+	}
+	// Still full: drop an arbitrary entry to make room.
+	for k := range a.cache {
+		if len(a.cache) < maxCacheEntries {
+			break
+		}
+		delete(a.cache, k)
+	}
+	a.cache[key] = cacheEntry{body: body, expires: now.Add(a.ttl)}
+}
+
+func writeBody(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+// epochSeconds renders t the way the AWS JSON protocol does.
+func epochSeconds(t *time.Time) float64 {
+	if t == nil {
+		return 0
+	}
+	return float64(t.UnixMilli()) / 1000
+}
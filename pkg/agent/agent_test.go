@@ -0,0 +1,190 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	smpkg "github.com/leneffets/awsserver/pkg/secretsmanager"
+	ssmpkg "github.com/leneffets/awsserver/pkg/ssm"
+)
+
+// mockSM implements only GetSecretValue; the embedded interface is nil, so
+// any other call panics.
+type mockSM struct {
+	smpkg.SecretsManagerAPI
+	input *secretsmanager.GetSecretValueInput
+	calls int
+	err   error
+}
+
+func (m *mockSM) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	m.input = params
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	created := time.UnixMilli(1700000000500)
+	return &secretsmanager.GetSecretValueOutput{
+		ARN:           aws.String("arn:aws:secretsmanager:eu-central-1:123456789012:secret:db-AbCdEf"),
+		Name:          params.SecretId,
+		SecretString:  aws.String(`{"password":"secret"}`),
+		VersionId:     aws.String("v1"),
+		VersionStages: []string{"AWSCURRENT"},
+		CreatedDate:   &created,
+	}, nil
+}
+
+type mockSSM struct {
+	ssmpkg.SSMAPI
+	input *ssm.GetParameterInput
+}
+
+func (m *mockSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	m.input = params
+	return &ssm.GetParameterOutput{Parameter: &types.Parameter{
+		Name:     aws.String("/app/db-host"),
+		Value:    aws.String("db.internal"),
+		Type:     types.ParameterTypeString,
+		Version:  3,
+		Selector: aws.String(":3"),
+	}}, nil
+}
+
+func get(t *testing.T, handler http.HandlerFunc, target, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if token != "" {
+		req.Header.Set(TokenHeader, token)
+	}
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	return rr
+}
+
+func TestHandleSecret(t *testing.T) {
+	sm := &mockSM{}
+	a := New(sm, &mockSSM{}, "tok", time.Minute)
+
+	rr := get(t, a.HandleSecret, "/secretsmanager/get?secretId=db&versionStage=AWSPREVIOUS", "tok")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := aws.ToString(sm.input.VersionStage); got != "AWSPREVIOUS" {
+		t.Errorf("expected version stage AWSPREVIOUS, got %q", got)
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["Name"] != "db" || resp["SecretString"] != `{"password":"secret"}` || resp["VersionId"] != "v1" {
+		t.Errorf("unexpected response %v", resp)
+	}
+	if resp["CreatedDate"] != 1700000000.5 {
+		t.Errorf("expected epoch seconds, got %v", resp["CreatedDate"])
+	}
+	if _, ok := resp["SecretBinary"]; ok {
+		t.Error("expected SecretBinary to be omitted")
+	}
+}
+
+func TestHandleSecret_Token(t *testing.T) {
+	a := New(&mockSM{}, &mockSSM{}, "tok", time.Minute)
+
+	for _, token := range []string{"", "wrong"} {
+		if rr := get(t, a.HandleSecret, "/secretsmanager/get?secretId=db", token); rr.Code != http.StatusForbidden {
+			t.Errorf("token %q: expected 403, got %d", token, rr.Code)
+		}
+	}
+
+	open := New(&mockSM{}, &mockSSM{}, "", time.Minute)
+	if rr := get(t, open.HandleSecret, "/secretsmanager/get?secretId=db", "anything"); rr.Code != http.StatusOK {
+		t.Errorf("expected any token to be accepted, got %d", rr.Code)
+	}
+	if rr := get(t, open.HandleSecret, "/secretsmanager/get?secretId=db", ""); rr.Code != http.StatusForbidden {
+		t.Errorf("expected missing token to be rejected, got %d", rr.Code)
+	}
+}
+
+func TestHandleSecret_Cache(t *testing.T) {
+	sm := &mockSM{}
+	a := New(sm, &mockSSM{}, "", time.Minute)
+
+	get(t, a.HandleSecret, "/secretsmanager/get?secretId=db", "x")
+	get(t, a.HandleSecret, "/secretsmanager/get?secretId=db", "x")
+	if sm.calls != 1 {
+		t.Errorf("expected cached response, got %d calls", sm.calls)
+	}
+
+	get(t, a.HandleSecret, "/secretsmanager/get?secretId=db&refreshNow=true", "x")
+	if sm.calls != 2 {
+		t.Errorf("expected refreshNow to bypass the cache, got %d calls", sm.calls)
+	}
+
+	uncached := New(sm, &mockSSM{}, "", 0)
+	get(t, uncached.HandleSecret, "/secretsmanager/get?secretId=db", "x")
+	get(t, uncached.HandleSecret, "/secretsmanager/get?secretId=db", "x")
+	if sm.calls != 4 {
+		t.Errorf("expected a zero TTL to disable caching, got %d calls", sm.calls)
+	}
+}
+
+func TestHandleSecret_AWSError(t *testing.T) {
+	sm := &mockSM{err: &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusBadRequest}},
+		Err:      errors.New("ResourceNotFoundException"),
+	}}}
+	a := New(sm, &mockSSM{}, "", time.Minute)
+
+	if rr := get(t, a.HandleSecret, "/secretsmanager/get?secretId=db", "x"); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected AWS status 400, got %d", rr.Code)
+	}
+
+	sm.err = errors.New("boom")
+	if rr := get(t, a.HandleSecret, "/secretsmanager/get?secretId=db", "x"); rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rr.Code)
+	}
+}
+
+func TestHandleParameter(t *testing.T) {
+	svc := &mockSSM{}
+	a := New(&mockSM{}, svc, "", time.Minute)
+
+	rr := get(t, a.HandleParameter, "/systems-manager/parameters/get?name=/app/db-host&version=3&withDecryption=true", "x")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := aws.ToString(svc.input.Name); got != "/app/db-host:3" {
+		t.Errorf("expected selector name, got %q", got)
+	}
+	if !aws.ToBool(svc.input.WithDecryption) {
+		t.Error("expected decryption to be requested")
+	}
+
+	var resp struct {
+		Parameter map[string]any
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Parameter["Value"] != "db.internal" || resp.Parameter["Version"] != float64(3) || resp.Parameter["Type"] != "String" {
+		t.Errorf("unexpected response %v", resp.Parameter)
+	}
+
+	for _, query := range []string{"version=1&label=prod", "version=abc", "version=0"} {
+		if rr := get(t, a.HandleParameter, "/systems-manager/parameters/get?name=x&"+query, "x"); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rr.Code)
+		}
+	}
+}
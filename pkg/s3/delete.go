@@ -0,0 +1,135 @@
+package s3
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// maxDeleteObjects is the number of keys DeleteObjects accepts per call.
+const maxDeleteObjects = 1000
+
+func DeleteObject(ctx context.Context, svc S3API, bucket, key string) error {
+	_, err := svc.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// DeleteObjects removes keys in batches of 1000 and returns the keys that
+// were deleted and the per-key errors S3 reported.
+func DeleteObjects(ctx context.Context, svc S3API, bucket string, keys []string) ([]string, []types.Error, error) {
+	deleted := []string{}
+	failed := []types.Error{}
+	for start := 0; start < len(keys); start += maxDeleteObjects {
+		end := min(start+maxDeleteObjects, len(keys))
+		objects := make([]types.ObjectIdentifier, 0, end-start)
+		for _, k := range keys[start:end] {
+			objects = append(objects, types.ObjectIdentifier{Key: aws.String(k)})
+		}
+		out, err := svc.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{Objects: objects},
+		})
+		if err != nil {
+			return deleted, failed, err
+		}
+		for _, d := range out.Deleted {
+			deleted = append(deleted, aws.ToString(d.Key))
+		}
+		failed = append(failed, out.Errors...)
+	}
+	return deleted, failed, nil
+}
+
+type deleteError struct {
+	Key     string `json:"key"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type deleteResponse struct {
+	Deleted []string      `json:"deleted"`
+	Errors  []deleteError `json:"errors"`
+	DryRun  bool          `json:"dry_run"`
+}
+
+// handleDeleteS3 deletes a single key, or every key under 'prefix'. With
+// 'dry_run=true' a prefix delete only reports the keys it would remove.
+func handleDeleteS3(w http.ResponseWriter, r *http.Request, svc S3API) {
+	query := r.URL.Query()
+	bucket, key, prefix := query.Get("bucket"), query.Get("key"), query.Get("prefix")
+	if bucket == "" || (key == "") == (prefix == "") {
+		http.Error(w, "Parameter 'bucket' and one of 'key' or 'prefix' are required", http.StatusBadRequest)
+		return
+	}
+
+	dryRun := false
+	if v := query.Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Parameter 'dry_run' must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// A large prefix takes one list and one delete call per 1000 keys, which
+	// can outlast the server's write timeout.
+	disableDeadline(w)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+
+	if key != "" {
+		if !dryRun {
+			if err := DeleteObject(ctx, svc, bucket, key); err != nil {
+				slog.Error("failed to delete file from S3", "bucket", bucket, "key", key, "error", err)
+				http.Error(w, "Error deleting file from S3", http.StatusInternalServerError)
+				return
+			}
+			slog.Info("file deleted", "bucket", bucket, "key", key)
+		}
+		writeJSON(w, deleteResponse{Deleted: []string{key}, Errors: []deleteError{}, DryRun: dryRun})
+		return
+	}
+
+	listed, err := ListObjects(ctx, svc, bucket, prefix, "")
+	if err != nil {
+		slog.Error("failed to list objects", "bucket", bucket, "prefix", prefix, "error", err)
+		http.Error(w, "Error listing objects", http.StatusInternalServerError)
+		return
+	}
+	keys := make([]string, 0, len(listed.Objects))
+	for _, o := range listed.Objects {
+		keys = append(keys, aws.ToString(o.Key))
+	}
+
+	if dryRun {
+		writeJSON(w, deleteResponse{Deleted: keys, Errors: []deleteError{}, DryRun: true})
+		return
+	}
+
+	deleted, failed, err := DeleteObjects(ctx, svc, bucket, keys)
+	if err != nil {
+		slog.Error("failed to delete objects", "bucket", bucket, "prefix", prefix, "deleted", len(deleted), "error", err)
+		http.Error(w, "Error deleting files from S3", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("prefix deleted", "bucket", bucket, "prefix", prefix, "deleted", len(deleted), "errors", len(failed))
+
+	resp := deleteResponse{Deleted: deleted, Errors: make([]deleteError, 0, len(failed))}
+	for _, e := range failed {
+		resp.Errors = append(resp.Errors, deleteError{
+			Key:     aws.ToString(e.Key),
+			Code:    aws.ToString(e.Code),
+			Message: aws.ToString(e.Message),
+		})
+	}
+	writeJSON(w, resp)
+}
@@ -0,0 +1,104 @@
+package s3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func objects(keys ...string) []types.Object {
+	var out []types.Object
+	for _, k := range keys {
+		out = append(out, types.Object{Key: aws.String(k)})
+	}
+	return out
+}
+
+func TestHandleDeleteS3_Key(t *testing.T) {
+	mock := &mockS3{}
+
+	req := httptest.NewRequest("DELETE", "/s3?bucket=b&key=k", nil)
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if mock.deleted == nil || aws.ToString(mock.deleted.Key) != "k" {
+		t.Error("expected DeleteObject to be called")
+	}
+}
+
+func TestHandleDeleteS3_Prefix(t *testing.T) {
+	keys := make([]string, 0, 1500)
+	for i := range 1500 {
+		keys = append(keys, fmt.Sprintf("branch/%04d", i))
+	}
+	keys = append(keys, "locked/x")
+	mock := &mockS3{listPages: map[string]s3.ListObjectsV2Output{
+		"": {Contents: objects(keys...)},
+	}}
+
+	req := httptest.NewRequest("DELETE", "/s3?bucket=b&prefix=branch/", nil)
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if len(mock.batches) != 2 || len(mock.batches[0]) != 1000 || len(mock.batches[1]) != 501 {
+		t.Errorf("expected batches of 1000, got %d batches", len(mock.batches))
+	}
+
+	var resp deleteResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Deleted) != 1500 || len(resp.Errors) != 1 || resp.Errors[0].Key != "locked/x" {
+		t.Errorf("unexpected response: %d deleted, errors %+v", len(resp.Deleted), resp.Errors)
+	}
+}
+
+func TestHandleDeleteS3_DryRun(t *testing.T) {
+	mock := &mockS3{listPages: map[string]s3.ListObjectsV2Output{
+		"": {Contents: objects("branch/a", "branch/b")},
+	}}
+
+	req := httptest.NewRequest("DELETE", "/s3?bucket=b&prefix=branch/&dry_run=true", nil)
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	var resp deleteResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.DryRun || len(resp.Deleted) != 2 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if len(mock.batches) != 0 {
+		t.Error("dry run must not delete")
+	}
+}
+
+func TestHandleDeleteS3_InvalidParams(t *testing.T) {
+	for _, target := range []string{
+		"/s3?bucket=b",
+		"/s3?key=k",
+		"/s3?bucket=b&key=k&prefix=p",
+		"/s3?bucket=b&prefix=p&dry_run=maybe",
+	} {
+		req := httptest.NewRequest("DELETE", target, nil)
+		rr := httptest.NewRecorder()
+		HandleS3(rr, req, &mockS3{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", target, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// conditions holds the range and conditional request headers forwarded to S3.
+type conditions struct {
+	Range             *string
+	IfMatch           *string
+	IfNoneMatch       *string
+	IfModifiedSince   *time.Time
+	IfUnmodifiedSince *time.Time
+}
+
+func requestConditions(r *http.Request) conditions {
+	var c conditions
+	if v := r.Header.Get("Range"); v != "" {
+		c.Range = aws.String(v)
+	}
+	if v := r.Header.Get("If-Match"); v != "" {
+		c.IfMatch = aws.String(v)
+	}
+	if v := r.Header.Get("If-None-Match"); v != "" {
+		c.IfNoneMatch = aws.String(v)
+	}
+	// Unparseable dates are ignored, as RFC 9110 requires.
+	if t, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		c.IfModifiedSince = &t
+	}
+	if t, err := http.ParseTime(r.Header.Get("If-Unmodified-Since")); err == nil {
+		c.IfUnmodifiedSince = &t
+	}
+	return c
+}
+
+// objectHeaders are the response headers passed through from S3.
+type objectHeaders struct {
+	ContentType   *string
+	ContentLength *int64
+	ContentRange  *string
+	ETag          *string
+	LastModified  *time.Time
+}
+
+func (o objectHeaders) write(h http.Header) {
+	h.Set("Accept-Ranges", "bytes")
+	if o.ContentType != nil {
+		h.Set("Content-Type", *o.ContentType)
+	} else {
+		h.Set("Content-Type", "application/octet-stream")
+	}
+	if o.ContentLength != nil {
+		h.Set("Content-Length", strconv.FormatInt(*o.ContentLength, 10))
+	}
+	if o.ContentRange != nil {
+		h.Set("Content-Range", *o.ContentRange)
+	}
+	if o.ETag != nil {
+		h.Set("ETag", *o.ETag)
+	}
+	if o.LastModified != nil {
+		h.Set("Last-Modified", o.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// handleGetS3 streams an object, honouring Range and conditional headers.
+// Downloads have no timeout so large files are not cut off.
+func handleGetS3(w http.ResponseWriter, r *http.Request, svc S3API, bucket, key string) {
+	c := requestConditions(r)
+	output, err := svc.GetObject(r.Context(), &s3.GetObjectInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		Range:             c.Range,
+		IfMatch:           c.IfMatch,
+		IfNoneMatch:       c.IfNoneMatch,
+		IfModifiedSince:   c.IfModifiedSince,
+		IfUnmodifiedSince: c.IfUnmodifiedSince,
+	})
+	if err != nil {
+		writeObjectError(w, "failed to fetch file from S3", err)
+		return
+	}
+	defer output.Body.Close()
+
+	objectHeaders{
+		ContentType:   output.ContentType,
+		ContentLength: output.ContentLength,
+		ContentRange:  output.ContentRange,
+		ETag:          output.ETag,
+		LastModified:  output.LastModified,
+	}.write(w.Header())
+
+	disableDeadline(w)
+	if output.ContentRange != nil {
+		w.WriteHeader(http.StatusPartialContent)
+	}
+	if _, err := io.Copy(w, output.Body); err != nil {
+		slog.Error("failed to send file", "error", err)
+	}
+}
+
+// handleHeadS3 answers with the object's headers only, via HeadObject.
+func handleHeadS3(w http.ResponseWriter, r *http.Request, svc S3API, bucket, key string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	c := requestConditions(r)
+	output, err := svc.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		Range:             c.Range,
+		IfMatch:           c.IfMatch,
+		IfNoneMatch:       c.IfNoneMatch,
+		IfModifiedSince:   c.IfModifiedSince,
+		IfUnmodifiedSince: c.IfUnmodifiedSince,
+	})
+	if err != nil {
+		writeObjectError(w, "failed to fetch file metadata from S3", err)
+		return
+	}
+
+	objectHeaders{
+		ContentType:   output.ContentType,
+		ContentLength: output.ContentLength,
+		ContentRange:  output.ContentRange,
+		ETag:          output.ETag,
+		LastModified:  output.LastModified,
+	}.write(w.Header())
+
+	if output.ContentRange != nil {
+		w.WriteHeader(http.StatusPartialContent)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeObjectError passes through the HTTP statuses S3 uses to answer
+// conditional and range requests, and for missing objects.
+func writeObjectError(w http.ResponseWriter, msg string, err error) {
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		switch status := respErr.HTTPStatusCode(); status {
+		case http.StatusNotModified:
+			w.WriteHeader(status)
+			return
+		case http.StatusNotFound:
+			http.Error(w, "File not found", status)
+			return
+		case http.StatusForbidden:
+			http.Error(w, "Access denied", status)
+			return
+		case http.StatusPreconditionFailed:
+			http.Error(w, "Precondition failed", status)
+			return
+		case http.StatusRequestedRangeNotSatisfiable:
+			http.Error(w, "Requested range not satisfiable", status)
+			return
+		}
+	}
+	slog.Error(msg, "error", err)
+	http.Error(w, "Error fetching file from S3", http.StatusInternalServerError)
+}
@@ -0,0 +1,125 @@
+package s3
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func responseError(status int) error {
+	return &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      errors.New(http.StatusText(status)),
+	}}
+}
+
+func TestHandleGetS3_Headers(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock := &mockS3{getResp: s3.GetObjectOutput{
+		Body:          io.NopCloser(strings.NewReader("content")),
+		ContentType:   aws.String("application/gzip"),
+		ContentLength: aws.Int64(7),
+		ETag:          aws.String(`"abc"`),
+		LastModified:  &modified,
+	}}
+
+	req := httptest.NewRequest("GET", "/s3?bucket=b&key=k", nil)
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	for header, want := range map[string]string{
+		"Content-Type":   "application/gzip",
+		"Content-Length": "7",
+		"ETag":           `"abc"`,
+		"Last-Modified":  "Wed, 01 May 2024 12:00:00 GMT",
+		"Accept-Ranges":  "bytes",
+	} {
+		if got := rr.Header().Get(header); got != want {
+			t.Errorf("%s: got %q want %q", header, got, want)
+		}
+	}
+}
+
+func TestHandleGetS3_Range(t *testing.T) {
+	mock := &mockS3{getResp: s3.GetObjectOutput{
+		Body:         io.NopCloser(strings.NewReader("nte")),
+		ContentRange: aws.String("bytes 2-4/7"),
+	}}
+
+	req := httptest.NewRequest("GET", "/s3?bucket=b&key=k", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusPartialContent {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusPartialContent)
+	}
+	if aws.ToString(mock.getInput.Range) != "bytes=2-4" {
+		t.Errorf("range not forwarded: %q", aws.ToString(mock.getInput.Range))
+	}
+	if rr.Header().Get("Content-Range") != "bytes 2-4/7" || rr.Body.String() != "nte" {
+		t.Errorf("unexpected response %q %q", rr.Header().Get("Content-Range"), rr.Body.String())
+	}
+}
+
+func TestHandleGetS3_Conditional(t *testing.T) {
+	mock := &mockS3{err: responseError(http.StatusNotModified)}
+
+	req := httptest.NewRequest("GET", "/s3?bucket=b&key=k", nil)
+	req.Header.Set("If-None-Match", `"abc"`)
+	req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 12:00:00 GMT")
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusNotModified {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusNotModified)
+	}
+	if aws.ToString(mock.getInput.IfNoneMatch) != `"abc"` || mock.getInput.IfModifiedSince == nil {
+		t.Errorf("conditions not forwarded: %+v", mock.getInput)
+	}
+}
+
+func TestHandleGetS3_StatusPassThrough(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusPreconditionFailed, http.StatusRequestedRangeNotSatisfiable} {
+		req := httptest.NewRequest("GET", "/s3?bucket=b&key=k", nil)
+		rr := httptest.NewRecorder()
+		HandleS3(rr, req, &mockS3{err: responseError(status)})
+
+		if rr.Code != status {
+			t.Errorf("got %d want %d", rr.Code, status)
+		}
+	}
+}
+
+func TestHandleHeadS3(t *testing.T) {
+	mock := &mockS3{headResp: s3.HeadObjectOutput{
+		ContentLength: aws.Int64(42),
+		ETag:          aws.String(`"abc"`),
+	}}
+
+	req := httptest.NewRequest("HEAD", "/s3?bucket=b&key=k", nil)
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if mock.headInput == nil || mock.getInput != nil {
+		t.Error("expected HeadObject to be used")
+	}
+	if rr.Header().Get("Content-Length") != "42" || rr.Header().Get("Content-Type") != "application/octet-stream" || rr.Body.Len() != 0 {
+		t.Errorf("unexpected response %v %q", rr.Header(), rr.Body.String())
+	}
+}
@@ -0,0 +1,118 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ListResult holds the objects and common prefixes found under a prefix.
+type ListResult struct {
+	Objects        []types.Object
+	CommonPrefixes []string
+}
+
+// ListObjects lists every object below prefix, following all result pages.
+// With a delimiter, keys containing it after the prefix are rolled up into
+// common prefixes instead.
+func ListObjects(ctx context.Context, svc S3API, bucket, prefix, delimiter string) (*ListResult, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	if delimiter != "" {
+		input.Delimiter = aws.String(delimiter)
+	}
+
+	result := &ListResult{}
+	paginator := s3.NewListObjectsV2Paginator(svc, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result.Objects = append(result.Objects, page.Contents...)
+		for _, p := range page.CommonPrefixes {
+			result.CommonPrefixes = append(result.CommonPrefixes, aws.ToString(p.Prefix))
+		}
+	}
+	return result, nil
+}
+
+type objectEntry struct {
+	Key          string     `json:"key"`
+	Size         int64      `json:"size"`
+	ETag         string     `json:"etag"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	StorageClass string     `json:"storage_class,omitempty"`
+}
+
+type listResponse struct {
+	Objects        []objectEntry `json:"objects"`
+	CommonPrefixes []string      `json:"common_prefixes"`
+}
+
+// HandleS3List serves GET /s3/list?bucket=&prefix=&delimiter=.
+func HandleS3List(w http.ResponseWriter, r *http.Request, svc S3API) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	bucket := query.Get("bucket")
+	if bucket == "" {
+		http.Error(w, "Parameter 'bucket' is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := ListObjects(ctx, svc, bucket, query.Get("prefix"), query.Get("delimiter"))
+	if err != nil {
+		var noBucket *types.NoSuchBucket
+		if errors.As(err, &noBucket) {
+			http.Error(w, "Bucket not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to list objects", "bucket", bucket, "error", err)
+		http.Error(w, "Error listing objects", http.StatusInternalServerError)
+		return
+	}
+
+	resp := listResponse{
+		Objects:        make([]objectEntry, 0, len(result.Objects)),
+		CommonPrefixes: result.CommonPrefixes,
+	}
+	if resp.CommonPrefixes == nil {
+		resp.CommonPrefixes = []string{}
+	}
+	for _, o := range result.Objects {
+		resp.Objects = append(resp.Objects, objectEntry{
+			Key:          aws.ToString(o.Key),
+			Size:         aws.ToInt64(o.Size),
+			ETag:         aws.ToString(o.ETag),
+			LastModified: o.LastModified,
+			StorageClass: string(o.StorageClass),
+		})
+	}
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to encode response", "error", err)
+	}
+}
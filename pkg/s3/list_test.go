@@ -0,0 +1,72 @@
+package s3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestHandleS3List(t *testing.T) {
+	mock := &mockS3{listPages: map[string]s3.ListObjectsV2Output{
+		"": {
+			Contents: []types.Object{
+				{Key: aws.String("builds/1.tar.gz"), Size: aws.Int64(10), ETag: aws.String(`"a"`)},
+			},
+			CommonPrefixes:        []types.CommonPrefix{{Prefix: aws.String("builds/old/")}},
+			IsTruncated:           aws.Bool(true),
+			NextContinuationToken: aws.String("next"),
+		},
+		"next": {
+			Contents: []types.Object{
+				{Key: aws.String("builds/2.tar.gz"), Size: aws.Int64(20), ETag: aws.String(`"b"`)},
+			},
+		},
+	}}
+
+	req := httptest.NewRequest("GET", "/s3/list?bucket=b&prefix=builds/&delimiter=/", nil)
+	rr := httptest.NewRecorder()
+	HandleS3List(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if aws.ToString(mock.listInput.Prefix) != "builds/" || aws.ToString(mock.listInput.Delimiter) != "/" {
+		t.Errorf("unexpected input %+v", mock.listInput)
+	}
+
+	var resp listResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Objects) != 2 || resp.Objects[1].Key != "builds/2.tar.gz" || resp.Objects[1].Size != 20 || resp.Objects[0].ETag != `"a"` {
+		t.Errorf("unexpected objects %+v", resp.Objects)
+	}
+	if len(resp.CommonPrefixes) != 1 || resp.CommonPrefixes[0] != "builds/old/" {
+		t.Errorf("unexpected prefixes %v", resp.CommonPrefixes)
+	}
+}
+
+func TestHandleS3List_MissingBucket(t *testing.T) {
+	req := httptest.NewRequest("GET", "/s3/list?prefix=x", nil)
+	rr := httptest.NewRecorder()
+	HandleS3List(rr, req, &mockS3{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleS3List_NoSuchBucket(t *testing.T) {
+	req := httptest.NewRequest("GET", "/s3/list?bucket=b", nil)
+	rr := httptest.NewRecorder()
+	HandleS3List(rr, req, &mockS3{err: &types.NoSuchBucket{}})
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
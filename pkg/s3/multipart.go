@@ -0,0 +1,374 @@
+package s3
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// CreateMultipartUpload starts a multipart upload and returns its upload ID.
+func CreateMultipartUpload(ctx context.Context, svc S3API, bucket, key, contentType string) (string, error) {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	out, err := svc.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+// UploadPart uploads one part of size bytes and returns its ETag.
+func UploadPart(ctx context.Context, svc S3API, bucket, key, uploadID string, partNumber int32, body io.Reader, size int64) (string, error) {
+	out, err := svc.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(partNumber),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+// ListParts returns every part uploaded so far, following all result pages.
+func ListParts(ctx context.Context, svc S3API, bucket, key, uploadID string) ([]types.Part, error) {
+	paginator := s3.NewListPartsPaginator(svc, &s3.ListPartsInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+
+	var parts []types.Part
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, page.Parts...)
+	}
+	return parts, nil
+}
+
+// ListMultipartUploads returns the uploads in progress in bucket whose key
+// starts with prefix, following all result pages.
+func ListMultipartUploads(ctx context.Context, svc S3API, bucket, prefix string) ([]types.MultipartUpload, error) {
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+
+	var uploads []types.MultipartUpload
+	paginator := s3.NewListMultipartUploadsPaginator(svc, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, page.Uploads...)
+	}
+	return uploads, nil
+}
+
+// CompleteMultipartUpload assembles the object from parts, which must be
+// sorted by part number.
+func CompleteMultipartUpload(ctx context.Context, svc S3API, bucket, key, uploadID string, parts []types.CompletedPart) (*s3.CompleteMultipartUploadOutput, error) {
+	return svc.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+}
+
+func AbortMultipartUpload(ctx context.Context, svc S3API, bucket, key, uploadID string) error {
+	_, err := svc.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
+type uploadResponse struct {
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	UploadID string `json:"upload_id"`
+}
+
+type uploadEntry struct {
+	Key       string     `json:"key"`
+	UploadID  string     `json:"upload_id"`
+	Initiated *time.Time `json:"initiated,omitempty"`
+}
+
+type partEntry struct {
+	PartNumber   int32      `json:"part_number"`
+	ETag         string     `json:"etag"`
+	Size         int64      `json:"size,omitempty"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+}
+
+type partsResponse struct {
+	UploadID string      `json:"upload_id"`
+	Parts    []partEntry `json:"parts"`
+}
+
+// completeRequest is the optional JSON body of POST /s3/multipart/complete.
+type completeRequest struct {
+	Parts []partEntry `json:"parts"`
+}
+
+type completeResponse struct {
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	ETag     string `json:"etag"`
+	Location string `json:"location,omitempty"`
+}
+
+// HandleS3Multipart lets clients drive a multipart upload themselves:
+// POST starts an upload, PUT uploads a part, GET lists the parts uploaded so
+// far (or, without 'upload_id', the uploads in progress) to resume after a
+// failure, and DELETE aborts the upload.
+func HandleS3Multipart(w http.ResponseWriter, r *http.Request, svc S3API) {
+	query := r.URL.Query()
+	bucket, key, uploadID := query.Get("bucket"), query.Get("key"), query.Get("upload_id")
+	if bucket == "" {
+		http.Error(w, "Parameter 'bucket' is required", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodGet && key == "" {
+		http.Error(w, "Parameter 'key' is required", http.StatusBadRequest)
+		return
+	}
+	if (r.Method == http.MethodPut || r.Method == http.MethodDelete) && uploadID == "" {
+		http.Error(w, "Parameter 'upload_id' is required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		handleCreateMultipart(w, r, svc, bucket, key)
+	case http.MethodPut:
+		handleUploadPart(w, r, svc, bucket, key, uploadID)
+	case http.MethodGet:
+		if uploadID == "" {
+			handleListUploads(w, r, svc, bucket, key)
+		} else if key == "" {
+			http.Error(w, "Parameter 'key' is required", http.StatusBadRequest)
+		} else {
+			handleListParts(w, r, svc, bucket, key, uploadID)
+		}
+	case http.MethodDelete:
+		handleAbortMultipart(w, r, svc, bucket, key, uploadID)
+	default:
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+	}
+}
+
+func handleCreateMultipart(w http.ResponseWriter, r *http.Request, svc S3API, bucket, key string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	uploadID, err := CreateMultipartUpload(ctx, svc, bucket, key, r.URL.Query().Get("content_type"))
+	if err != nil {
+		writeMultipartError(w, "failed to create multipart upload", err)
+		return
+	}
+	slog.Info("multipart upload created", "bucket", bucket, "key", key, "upload_id", uploadID)
+	writeJSON(w, uploadResponse{Bucket: bucket, Key: key, UploadID: uploadID})
+}
+
+func handleUploadPart(w http.ResponseWriter, r *http.Request, svc S3API, bucket, key, uploadID string) {
+	partNumber, err := strconv.ParseInt(r.URL.Query().Get("part_number"), 10, 32)
+	if err != nil || partNumber < 1 || partNumber > 10000 {
+		http.Error(w, "Parameter 'part_number' must be between 1 and 10000", http.StatusBadRequest)
+		return
+	}
+	// S3 needs the part size up front; a streamed part cannot be buffered.
+	if r.ContentLength < 0 {
+		http.Error(w, "Content-Length is required", http.StatusLengthRequired)
+		return
+	}
+	if r.ContentLength > maxPartSize {
+		http.Error(w, "Part exceeds the maximum part size of 5 GiB", http.StatusRequestEntityTooLarge)
+		return
+	}
+	disableDeadline(w)
+
+	etag, err := UploadPart(r.Context(), svc, bucket, key, uploadID, int32(partNumber), r.Body, r.ContentLength)
+	if err != nil {
+		writeMultipartError(w, "failed to upload part", err)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	writeJSON(w, partEntry{PartNumber: int32(partNumber), ETag: etag, Size: r.ContentLength})
+}
+
+func handleListParts(w http.ResponseWriter, r *http.Request, svc S3API, bucket, key, uploadID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	parts, err := ListParts(ctx, svc, bucket, key, uploadID)
+	if err != nil {
+		writeMultipartError(w, "failed to list parts", err)
+		return
+	}
+
+	resp := partsResponse{UploadID: uploadID, Parts: make([]partEntry, 0, len(parts))}
+	for _, p := range parts {
+		resp.Parts = append(resp.Parts, partEntry{
+			PartNumber:   aws.ToInt32(p.PartNumber),
+			ETag:         aws.ToString(p.ETag),
+			Size:         aws.ToInt64(p.Size),
+			LastModified: p.LastModified,
+		})
+	}
+	writeJSON(w, resp)
+}
+
+func handleListUploads(w http.ResponseWriter, r *http.Request, svc S3API, bucket, prefix string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	uploads, err := ListMultipartUploads(ctx, svc, bucket, prefix)
+	if err != nil {
+		writeMultipartError(w, "failed to list multipart uploads", err)
+		return
+	}
+
+	resp := make([]uploadEntry, 0, len(uploads))
+	for _, u := range uploads {
+		resp = append(resp, uploadEntry{
+			Key:       aws.ToString(u.Key),
+			UploadID:  aws.ToString(u.UploadId),
+			Initiated: u.Initiated,
+		})
+	}
+	writeJSON(w, resp)
+}
+
+func handleAbortMultipart(w http.ResponseWriter, r *http.Request, svc S3API, bucket, key, uploadID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := AbortMultipartUpload(ctx, svc, bucket, key, uploadID); err != nil {
+		writeMultipartError(w, "failed to abort multipart upload", err)
+		return
+	}
+	slog.Info("multipart upload aborted", "bucket", bucket, "key", key, "upload_id", uploadID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleS3MultipartComplete finishes a multipart upload. The parts are taken
+// from a JSON body of the form {"parts":[{"part_number":1,"etag":"..."}]} or,
+// without a body, from the parts S3 has recorded for the upload.
+func HandleS3MultipartComplete(w http.ResponseWriter, r *http.Request, svc S3API) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	bucket, key, uploadID := query.Get("bucket"), query.Get("key"), query.Get("upload_id")
+	if bucket == "" || key == "" || uploadID == "" {
+		http.Error(w, "Parameters 'bucket', 'key' and 'upload_id' are required", http.StatusBadRequest)
+		return
+	}
+
+	var req completeRequest
+	if isJSON(r) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Completing thousands of large parts can take S3 minutes, so like the
+	// streamed uploads this runs without a timeout until the client goes away.
+	disableDeadline(w)
+	ctx := r.Context()
+
+	if len(req.Parts) == 0 {
+		parts, err := ListParts(ctx, svc, bucket, key, uploadID)
+		if err != nil {
+			writeMultipartError(w, "failed to list parts", err)
+			return
+		}
+		for _, p := range parts {
+			req.Parts = append(req.Parts, partEntry{PartNumber: aws.ToInt32(p.PartNumber), ETag: aws.ToString(p.ETag)})
+		}
+		if len(req.Parts) == 0 {
+			http.Error(w, "Upload has no parts", http.StatusBadRequest)
+			return
+		}
+	}
+
+	slices.SortFunc(req.Parts, func(a, b partEntry) int { return cmp.Compare(a.PartNumber, b.PartNumber) })
+	completed := make([]types.CompletedPart, 0, len(req.Parts))
+	for _, p := range req.Parts {
+		if p.PartNumber < 1 || p.ETag == "" {
+			http.Error(w, "Every part needs a 'part_number' and an 'etag'", http.StatusBadRequest)
+			return
+		}
+		completed = append(completed, types.CompletedPart{PartNumber: aws.Int32(p.PartNumber), ETag: aws.String(p.ETag)})
+	}
+
+	out, err := CompleteMultipartUpload(ctx, svc, bucket, key, uploadID, completed)
+	if err != nil {
+		writeMultipartError(w, "failed to complete multipart upload", err)
+		return
+	}
+	slog.Info("multipart upload completed", "bucket", bucket, "key", key, "upload_id", uploadID, "parts", len(completed))
+	writeJSON(w, completeResponse{
+		Bucket:   bucket,
+		Key:      key,
+		ETag:     aws.ToString(out.ETag),
+		Location: aws.ToString(out.Location),
+	})
+}
+
+// writeMultipartError maps a missing upload to 404 and a part list S3
+// rejects to 400.
+func writeMultipartError(w http.ResponseWriter, msg string, err error) {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchUpload":
+			http.Error(w, "Multipart upload not found", http.StatusNotFound)
+			return
+		case "InvalidPart", "InvalidPartOrder", "EntityTooSmall":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	slog.Error(msg, "error", err)
+	http.Error(w, "Error processing multipart upload", http.StatusInternalServerError)
+}
+
+func isJSON(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
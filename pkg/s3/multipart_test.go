@@ -0,0 +1,220 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestHandleS3Multipart_Create(t *testing.T) {
+	req := httptest.NewRequest("POST", "/s3/multipart?bucket=b&key=k", nil)
+	rr := httptest.NewRecorder()
+	HandleS3Multipart(rr, req, &mockS3{})
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	var resp uploadResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.UploadID != "upload-1" {
+		t.Errorf("got upload id %q", resp.UploadID)
+	}
+}
+
+func TestHandleS3Multipart_UploadPart(t *testing.T) {
+	mock := &mockS3{}
+
+	req := httptest.NewRequest("PUT", "/s3/multipart?bucket=b&key=k&upload_id=u&part_number=2", strings.NewReader("part"))
+	rr := httptest.NewRecorder()
+	HandleS3Multipart(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if rr.Header().Get("ETag") != `"etag-2"` {
+		t.Errorf("got etag %q", rr.Header().Get("ETag"))
+	}
+	if aws.ToInt32(mock.partInput.PartNumber) != 2 || aws.ToInt64(mock.partInput.ContentLength) != 4 || aws.ToString(mock.partInput.UploadId) != "u" {
+		t.Errorf("unexpected input %+v", mock.partInput)
+	}
+}
+
+func TestHandleS3Multipart_UploadPartValidation(t *testing.T) {
+	for _, target := range []string{
+		"/s3/multipart?bucket=b&key=k&part_number=1",
+		"/s3/multipart?bucket=b&key=k&upload_id=u",
+		"/s3/multipart?bucket=b&key=k&upload_id=u&part_number=10001",
+	} {
+		req := httptest.NewRequest("PUT", target, strings.NewReader("part"))
+		rr := httptest.NewRecorder()
+		HandleS3Multipart(rr, req, &mockS3{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", target, rr.Code, http.StatusBadRequest)
+		}
+	}
+
+	req := httptest.NewRequest("PUT", "/s3/multipart?bucket=b&key=k&upload_id=u&part_number=1", strings.NewReader("part"))
+	req.ContentLength = -1
+	rr := httptest.NewRecorder()
+	HandleS3Multipart(rr, req, &mockS3{})
+	if rr.Code != http.StatusLengthRequired {
+		t.Errorf("got %d want %d", rr.Code, http.StatusLengthRequired)
+	}
+}
+
+func TestHandleS3Multipart_ListParts(t *testing.T) {
+	mock := &mockS3{listParts: []types.Part{
+		{PartNumber: aws.Int32(1), ETag: aws.String(`"a"`), Size: aws.Int64(5)},
+	}}
+
+	req := httptest.NewRequest("GET", "/s3/multipart?bucket=b&key=k&upload_id=u", nil)
+	rr := httptest.NewRecorder()
+	HandleS3Multipart(rr, req, mock)
+
+	var resp partsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Parts) != 1 || resp.Parts[0].PartNumber != 1 || resp.Parts[0].ETag != `"a"` {
+		t.Errorf("unexpected parts %+v", resp.Parts)
+	}
+}
+
+func TestHandleS3Multipart_ListUploads(t *testing.T) {
+	mock := &mockS3{uploads: []types.MultipartUpload{
+		{Key: aws.String("cache.tar"), UploadId: aws.String("u")},
+	}}
+
+	req := httptest.NewRequest("GET", "/s3/multipart?bucket=b", nil)
+	rr := httptest.NewRecorder()
+	HandleS3Multipart(rr, req, mock)
+
+	var resp []uploadEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp) != 1 || resp[0].UploadID != "u" {
+		t.Errorf("unexpected uploads %+v", resp)
+	}
+}
+
+func TestHandleS3Multipart_Abort(t *testing.T) {
+	mock := &mockS3{}
+
+	req := httptest.NewRequest("DELETE", "/s3/multipart?bucket=b&key=k&upload_id=u", nil)
+	rr := httptest.NewRecorder()
+	HandleS3Multipart(rr, req, mock)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNoContent)
+	}
+	if mock.aborted == nil || aws.ToString(mock.aborted.UploadId) != "u" {
+		t.Error("expected upload to be aborted")
+	}
+}
+
+func TestHandleS3Multipart_NoSuchUpload(t *testing.T) {
+	req := httptest.NewRequest("DELETE", "/s3/multipart?bucket=b&key=k&upload_id=u", nil)
+	rr := httptest.NewRecorder()
+	HandleS3Multipart(rr, req, &mockS3{err: &types.NoSuchUpload{}})
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleS3MultipartComplete(t *testing.T) {
+	mock := &mockS3{}
+
+	body := `{"parts":[{"part_number":2,"etag":"b"},{"part_number":1,"etag":"a"}]}`
+	req := httptest.NewRequest("POST", "/s3/multipart/complete?bucket=b&key=k&upload_id=u", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	HandleS3MultipartComplete(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	parts := mock.completed.MultipartUpload.Parts
+	if len(parts) != 2 || aws.ToInt32(parts[0].PartNumber) != 1 || aws.ToString(parts[1].ETag) != "b" {
+		t.Errorf("expected parts sorted by number, got %+v", parts)
+	}
+}
+
+func TestHandleS3MultipartComplete_FromListedParts(t *testing.T) {
+	mock := &mockS3{listParts: []types.Part{
+		{PartNumber: aws.Int32(1), ETag: aws.String("a")},
+		{PartNumber: aws.Int32(2), ETag: aws.String("b")},
+	}}
+
+	req := httptest.NewRequest("POST", "/s3/multipart/complete?bucket=b&key=k&upload_id=u", nil)
+	rr := httptest.NewRecorder()
+	HandleS3MultipartComplete(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if len(mock.completed.MultipartUpload.Parts) != 2 {
+		t.Errorf("expected listed parts to be completed")
+	}
+}
+
+func TestUploadToS3_AbortsOnCancel(t *testing.T) {
+	mock := &mockS3{partErr: errors.New("connection reset")}
+	body := bytes.Repeat([]byte("x"), int(2*manager.DefaultUploadPartSize+1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := UploadToS3(ctx, mock, "b", "k", "", io.NopCloser(bytes.NewReader(body)), UploadOptions{Concurrency: 1})
+	if err == nil {
+		t.Fatal("expected upload to fail")
+	}
+	if mock.aborted == nil || aws.ToString(mock.aborted.UploadId) != "upload-1" {
+		t.Error("expected failed upload to be aborted")
+	}
+}
+
+func TestHandlePutS3_InvalidOptions(t *testing.T) {
+	for _, q := range []string{"part_size=1024", "concurrency=0", "concurrency=x"} {
+		req := httptest.NewRequest("PUT", "/s3?bucket=b&key=k&"+q, strings.NewReader("x"))
+		rr := httptest.NewRecorder()
+		HandleS3(rr, req, &mockS3{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", q, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandlePutS3_BufferLimit(t *testing.T) {
+	for _, q := range []string{
+		"part_size=268435456",
+		"part_size=134217728&concurrency=4",
+		"part_size=16777216&concurrency=32",
+	} {
+		mock := &mockS3{}
+		req := httptest.NewRequest("PUT", "/s3?bucket=b&key=k&"+q, strings.NewReader("x"))
+		rr := httptest.NewRecorder()
+		HandleS3(rr, req, mock)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", q, rr.Code, http.StatusBadRequest)
+		}
+		if mock.putInput != nil {
+			t.Errorf("%s: expected no upload", q)
+		}
+	}
+}
@@ -8,24 +8,115 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 type mockS3 struct {
-	getResp s3.GetObjectOutput
-	err     error
+	getResp   s3.GetObjectOutput
+	getInput  *s3.GetObjectInput
+	headResp  s3.HeadObjectOutput
+	headInput *s3.HeadObjectInput
+	listPages map[string]s3.ListObjectsV2Output
+	listInput *s3.ListObjectsV2Input
+	putInput  *s3.PutObjectInput
+	putBody   []byte
+	parts     int32
+	completed *s3.CompleteMultipartUploadInput
+	aborted   *s3.AbortMultipartUploadInput
+	partInput *s3.UploadPartInput
+	listParts []types.Part
+	uploads   []types.MultipartUpload
+	partErr   error
+	deleted   *s3.DeleteObjectInput
+	batches   [][]string
+	err       error
 }
 
 func (m *mockS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	m.getInput = params
 	return &m.getResp, m.err
 }
 
+func (m *mockS3) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	m.deleted = params
+	return &s3.DeleteObjectOutput{}, m.err
+}
+
+// DeleteObjects deletes every key except those starting with "locked/",
+// which are reported as errors.
+func (m *mockS3) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	out := &s3.DeleteObjectsOutput{}
+	var batch []string
+	for _, o := range params.Delete.Objects {
+		batch = append(batch, aws.ToString(o.Key))
+		if strings.HasPrefix(aws.ToString(o.Key), "locked/") {
+			out.Errors = append(out.Errors, types.Error{Key: o.Key, Code: aws.String("AccessDenied"), Message: aws.String("Access Denied")})
+		} else {
+			out.Deleted = append(out.Deleted, types.DeletedObject{Key: o.Key})
+		}
+	}
+	m.batches = append(m.batches, batch)
+	return out, m.err
+}
+
+func (m *mockS3) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	m.headInput = params
+	return &m.headResp, m.err
+}
+
 func (m *mockS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	m.putInput = params
+	if params.Body != nil {
+		m.putBody, _ = io.ReadAll(params.Body)
+	}
 	return &s3.PutObjectOutput{}, m.err
 }
 
+func (m *mockS3) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	m.listInput = params
+	page := m.listPages[aws.ToString(params.ContinuationToken)]
+	return &page, m.err
+}
+
+func (m *mockS3) CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1")}, m.err
+}
+
+func (m *mockS3) UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	if atomic.AddInt32(&m.parts, 1) == 1 {
+		m.partInput = params
+	}
+	io.Copy(io.Discard, params.Body)
+	if m.partErr != nil {
+		return nil, m.partErr
+	}
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf(`"etag-%d"`, aws.ToInt32(params.PartNumber)))}, m.err
+}
+
+func (m *mockS3) CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	m.completed = params
+	return &s3.CompleteMultipartUploadOutput{}, m.err
+}
+
+func (m *mockS3) AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	m.aborted = params
+	return &s3.AbortMultipartUploadOutput{}, m.err
+}
+
+func (m *mockS3) ListParts(ctx context.Context, params *s3.ListPartsInput, optFns ...func(*s3.Options)) (*s3.ListPartsOutput, error) {
+	return &s3.ListPartsOutput{Parts: m.listParts}, m.err
+}
+
+func (m *mockS3) ListMultipartUploads(ctx context.Context, params *s3.ListMultipartUploadsInput, optFns ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+	return &s3.ListMultipartUploadsOutput{Uploads: m.uploads}, m.err
+}
+
 func TestHandleGetS3(t *testing.T) {
 	mock := &mockS3{getResp: s3.GetObjectOutput{
 		Body: io.NopCloser(bytes.NewReader([]byte("file_content"))),
@@ -95,7 +186,7 @@ func TestHandlePostS3_NoFile(t *testing.T) {
 }
 
 func TestHandleS3_MethodNotAllowed(t *testing.T) {
-	req := httptest.NewRequest("DELETE", "/s3?bucket=b&key=k", nil)
+	req := httptest.NewRequest("PATCH", "/s3?bucket=b&key=k", nil)
 	rr := httptest.NewRecorder()
 	HandleS3(rr, req, &mockS3{})
 
@@ -0,0 +1,191 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// maxUploadConcurrency caps the number of parts uploaded in parallel.
+const maxUploadConcurrency = 32
+
+// maxPartSize is the largest part S3 accepts.
+const maxPartSize = 5 * 1024 * 1024 * 1024
+
+// maxUploadPartSize caps 'part_size' for streamed uploads, which buffer each
+// part in memory.
+const maxUploadPartSize = 128 * 1024 * 1024
+
+// maxUploadBuffer caps the memory one streamed upload may hold: the uploader
+// keeps a buffer per concurrent part plus the one being read.
+const maxUploadBuffer = 512 * 1024 * 1024
+
+// UploadOptions tunes multipart uploads. Zero values use the transfer
+// manager's defaults of 5 MiB parts and 5 concurrent part uploads.
+type UploadOptions struct {
+	PartSize    int64
+	Concurrency int
+}
+
+// UploadToS3 streams body to S3 without knowing its length up front. Bodies
+// smaller than one part are sent with a single PutObject, larger ones as a
+// multipart upload, so memory use is bounded by part size times concurrency.
+// A failed multipart upload is aborted even when ctx was cancelled, so a
+// client disconnect does not leave orphaned parts behind.
+func UploadToS3(ctx context.Context, svc S3API, bucket, key, contentType string, body io.Reader, opts UploadOptions) (*manager.UploadOutput, error) {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	uploader := manager.NewUploader(svc, func(u *manager.Uploader) {
+		if opts.PartSize > 0 {
+			u.PartSize = opts.PartSize
+		}
+		if opts.Concurrency > 0 {
+			u.Concurrency = opts.Concurrency
+		}
+		// The uploader would abort with the request context, which is already
+		// cancelled when the client went away; abort below instead.
+		u.LeavePartsOnError = true
+	})
+	out, err := uploader.Upload(ctx, input)
+
+	var failure manager.MultiUploadFailure
+	if errors.As(err, &failure) && failure.UploadID() != "" {
+		abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if abortErr := AbortMultipartUpload(abortCtx, svc, bucket, key, failure.UploadID()); abortErr != nil {
+			slog.Error("failed to abort multipart upload", "bucket", bucket, "key", key, "upload_id", failure.UploadID(), "error", abortErr)
+		}
+	}
+	return out, err
+}
+
+// uploadOptions reads the optional 'part_size' (bytes) and 'concurrency'
+// query parameters and rejects combinations that would buffer more than
+// maxUploadBuffer.
+func uploadOptions(r *http.Request) (UploadOptions, error) {
+	var opts UploadOptions
+	query := r.URL.Query()
+	if v := query.Get("part_size"); v != "" {
+		size, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || size < manager.MinUploadPartSize || size > maxUploadPartSize {
+			return opts, fmt.Errorf("Parameter 'part_size' must be between %d and %d bytes", manager.MinUploadPartSize, maxUploadPartSize)
+		}
+		opts.PartSize = size
+	}
+	if v := query.Get("concurrency"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxUploadConcurrency {
+			return opts, fmt.Errorf("Parameter 'concurrency' must be between 1 and %d", maxUploadConcurrency)
+		}
+		opts.Concurrency = n
+	}
+
+	partSize, concurrency := opts.PartSize, opts.Concurrency
+	if partSize == 0 {
+		partSize = manager.DefaultUploadPartSize
+	}
+	if concurrency == 0 {
+		concurrency = manager.DefaultUploadConcurrency
+	}
+	if partSize*int64(concurrency+1) > maxUploadBuffer {
+		return opts, fmt.Errorf("Parameters 'part_size' and 'concurrency' would buffer more than %d bytes", maxUploadBuffer)
+	}
+	return opts, nil
+}
+
+// handlePutS3 uploads the raw request body.
+func handlePutS3(w http.ResponseWriter, r *http.Request, svc S3API, bucket, key string) {
+	opts, err := uploadOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	disableDeadline(w)
+
+	if err := uploadStream(r.Context(), svc, bucket, key, r.Header.Get("Content-Type"), r.Body, opts); err != nil {
+		http.Error(w, "Error uploading file to S3", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handlePostS3 uploads the 'file' field of a multipart form, streaming it
+// straight from the request instead of buffering it on disk.
+func handlePostS3(w http.ResponseWriter, r *http.Request, svc S3API, bucket, key string) {
+	opts, err := uploadOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	disableDeadline(w)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Error reading uploaded file", http.StatusBadRequest)
+		slog.Error("failed to read uploaded file", "error", err)
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			http.Error(w, "Error reading uploaded file", http.StatusBadRequest)
+			if !errors.Is(err, io.EOF) {
+				slog.Error("failed to read uploaded file", "error", err)
+			}
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		err = uploadStream(r.Context(), svc, bucket, key, part.Header.Get("Content-Type"), part, opts)
+		part.Close()
+		if err != nil {
+			http.Error(w, "Error uploading file to S3", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+}
+
+func uploadStream(ctx context.Context, svc S3API, bucket, key, contentType string, body io.Reader, opts UploadOptions) error {
+	if _, err := UploadToS3(ctx, svc, bucket, key, contentType, body, opts); err != nil {
+		slog.Error("failed to upload file to S3", "bucket", bucket, "key", key, "error", err)
+		return err
+	}
+	slog.Info("file uploaded", "bucket", bucket, "key", key)
+	return nil
+}
+
+// disableDeadline lifts the server's read and write timeouts for the current
+// request, which would otherwise cut off large transfers.
+func disableDeadline(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	for _, err := range []error{
+		rc.SetReadDeadline(time.Time{}),
+		rc.SetWriteDeadline(time.Time{}),
+	} {
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.Error("failed to clear connection deadline", "error", err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package s3
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+)
+
+func TestHandlePutS3(t *testing.T) {
+	mock := &mockS3{}
+
+	req := httptest.NewRequest("PUT", "/s3?bucket=b&key=k", strings.NewReader("raw content"))
+	req.Header.Set("Content-Type", "text/plain")
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if string(mock.putBody) != "raw content" {
+		t.Errorf("got body %q", mock.putBody)
+	}
+	if aws.ToString(mock.putInput.ContentType) != "text/plain" {
+		t.Errorf("got content type %q", aws.ToString(mock.putInput.ContentType))
+	}
+}
+
+func TestHandlePutS3_Multipart(t *testing.T) {
+	mock := &mockS3{}
+	body := bytes.Repeat([]byte("x"), int(2*manager.DefaultUploadPartSize+1))
+
+	req := httptest.NewRequest("PUT", "/s3?bucket=b&key=k", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if mock.parts != 3 {
+		t.Errorf("got %d parts want 3", mock.parts)
+	}
+	if mock.completed == nil || len(mock.completed.MultipartUpload.Parts) != 3 {
+		t.Errorf("expected upload to be completed with 3 parts")
+	}
+}
+
+func TestHandlePostS3_StreamsFilePart(t *testing.T) {
+	mock := &mockS3{}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("comment", "ignored")
+	part, _ := w.CreateFormFile("file", "test.txt")
+	part.Write([]byte("content"))
+	w.Close()
+
+	req := httptest.NewRequest("POST", "/s3?bucket=b&key=k", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if string(mock.putBody) != "content" {
+		t.Errorf("got body %q", mock.putBody)
+	}
+}
+
+func TestHandlePostS3_MissingFileField(t *testing.T) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("other", "value")
+	w.Close()
+
+	req := httptest.NewRequest("POST", "/s3?bucket=b&key=k", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	rr := httptest.NewRecorder()
+	HandleS3(rr, req, &mockS3{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,189 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// maxBatchSecretIDs is the number of secret IDs BatchGetSecretValue accepts
+// per call.
+const maxBatchSecretIDs = 20
+
+// BatchGetSecrets reads several secrets at once, either by ID in chunks of
+// maxBatchSecretIDs or by filters, following all result pages. Secrets that
+// could not be read are reported in the returned errors instead of failing the
+// whole call.
+func BatchGetSecrets(ctx context.Context, svc SecretsManagerAPI, ids []string, filters []types.Filter) ([]types.SecretValueEntry, []types.APIErrorType, error) {
+	var values []types.SecretValueEntry
+	var apiErrors []types.APIErrorType
+
+	if len(filters) > 0 {
+		paginator := secretsmanager.NewBatchGetSecretValuePaginator(svc, &secretsmanager.BatchGetSecretValueInput{
+			Filters: filters,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			values = append(values, page.SecretValues...)
+			apiErrors = append(apiErrors, page.Errors...)
+		}
+		return values, apiErrors, nil
+	}
+
+	for start := 0; start < len(ids); start += maxBatchSecretIDs {
+		end := min(start+maxBatchSecretIDs, len(ids))
+		out, err := svc.BatchGetSecretValue(ctx, &secretsmanager.BatchGetSecretValueInput{
+			SecretIdList: ids[start:end],
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		values = append(values, out.SecretValues...)
+		apiErrors = append(apiErrors, out.Errors...)
+	}
+	return values, apiErrors, nil
+}
+
+type batchError struct {
+	SecretID  string `json:"secret_id"`
+	ErrorCode string `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+type batchResponse struct {
+	Secrets map[string]string `json:"secrets"`
+	Errors  []batchError      `json:"errors"`
+}
+
+// isBatchRequest reports whether a GET on /secrets asks for several secrets:
+// more than one 'name', any 'filter', or an explicit 'batch=true'.
+func isBatchRequest(r *http.Request) bool {
+	query := r.URL.Query()
+	return len(query["name"]) > 1 || len(query["filter"]) > 0 || query.Get("batch") == "true"
+}
+
+// parseBatchFilters turns repeated 'filter=key=value' query parameters into
+// BatchGetSecretValue filters, grouping values of the same key.
+func parseBatchFilters(values []string) ([]types.Filter, bool) {
+	var filters []types.Filter
+	for _, f := range values {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || !slices.Contains(types.FilterNameStringTypeAll.Values(), types.FilterNameStringType(key)) {
+			return nil, false
+		}
+		idx := slices.IndexFunc(filters, func(f types.Filter) bool { return string(f.Key) == key })
+		if idx < 0 {
+			filters = append(filters, types.Filter{Key: types.FilterNameStringType(key)})
+			idx = len(filters) - 1
+		}
+		filters[idx].Values = append(filters[idx].Values, value)
+	}
+	return filters, true
+}
+
+// singleSecretParams are the GET /secrets parameters that only apply to a
+// single secret.
+var singleSecretParams = []string{"version_id", "version_stage", "key", "pointer", "encoding", "format"}
+
+// handleBatchGetSecret serves several secrets as a JSON object of the
+// requested ID (or, for filters, the name) to value, plus the secrets that
+// could not be read. Binary values are base64 encoded.
+func handleBatchGetSecret(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	query := r.URL.Query()
+	for _, param := range singleSecretParams {
+		if query.Has(param) {
+			http.Error(w, "Parameter '"+param+"' is not supported when fetching several secrets", http.StatusBadRequest)
+			return
+		}
+	}
+	ids := uniqueNonEmpty(query["name"])
+
+	filters, ok := parseBatchFilters(query["filter"])
+	if !ok {
+		http.Error(w, "Parameter 'filter' must be of the form key=value with key one of "+
+			"name, description, tag-key, tag-value, primary-region, owning-service or all", http.StatusBadRequest)
+		return
+	}
+	if len(ids) > 0 && len(filters) > 0 {
+		http.Error(w, "Parameters 'name' and 'filter' are mutually exclusive", http.StatusBadRequest)
+		return
+	}
+	if len(ids) == 0 && len(filters) == 0 {
+		http.Error(w, "Parameter 'name' or 'filter' is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	values, apiErrors, err := BatchGetSecrets(ctx, svc, ids, filters)
+	if err != nil {
+		slog.Error("failed to fetch secrets", "error", err)
+		http.Error(w, "Error fetching secrets", http.StatusInternalServerError)
+		return
+	}
+
+	result := batchResponse{
+		Secrets: make(map[string]string, len(values)),
+		Errors:  make([]batchError, 0, len(apiErrors)),
+	}
+	for _, v := range values {
+		id := requestedID(ids, v)
+		if v.SecretString != nil {
+			result.Secrets[id] = *v.SecretString
+		} else {
+			result.Secrets[id] = base64.StdEncoding.EncodeToString(v.SecretBinary)
+		}
+	}
+	for _, e := range apiErrors {
+		result.Errors = append(result.Errors, batchError{
+			SecretID:  aws.ToString(e.SecretId),
+			ErrorCode: aws.ToString(e.ErrorCode),
+			Message:   aws.ToString(e.Message),
+		})
+	}
+
+	writeJSON(w, result)
+}
+
+// requestedID returns the entry of ids that v was fetched by, so callers can
+// match results to names, full ARNs or partial ARNs they asked for. It falls
+// back to the secret name when v was selected by a filter.
+func requestedID(ids []string, v types.SecretValueEntry) string {
+	name, arn := aws.ToString(v.Name), aws.ToString(v.ARN)
+	for _, id := range ids {
+		if id == name || id == arn {
+			return id
+		}
+		// A partial ARN lacks the "-" and six random characters AWS appends.
+		if suffix, ok := strings.CutPrefix(arn, id+"-"); ok && len(suffix) == 6 {
+			return id
+		}
+	}
+	return name
+}
+
+// uniqueNonEmpty drops empty and repeated names while keeping their order.
+func uniqueNonEmpty(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var out []string
+	for _, n := range names {
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package secretsmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNotJSON     = errors.New("secret is not a JSON document")
+	errKeyNotFound = errors.New("key not found in secret")
+	errBadPointer  = errors.New("invalid JSON pointer")
+)
+
+// extractField returns the value addressed by a top-level key or an RFC 6901
+// JSON pointer inside a JSON secret. Strings are returned raw, every other
+// value as compact JSON.
+func extractField(secret, key, pointer string) ([]byte, error) {
+	dec := json.NewDecoder(strings.NewReader(secret))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errNotJSON
+	}
+
+	var value any
+	var err error
+	if pointer != "" {
+		value, err = resolvePointer(doc, pointer)
+	} else {
+		value, err = resolvePointer(doc, "/"+escapePointerToken(key))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// resolvePointer walks doc along an RFC 6901 JSON pointer.
+func resolvePointer(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errBadPointer
+	}
+
+	current := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[token]
+			if !ok {
+				return nil, errKeyNotFound
+			}
+			current = next
+		case []any:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(node) || (len(token) > 1 && token[0] == '0') {
+				return nil, errKeyNotFound
+			}
+			current = node[idx]
+		default:
+			return nil, errKeyNotFound
+		}
+	}
+	return current, nil
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
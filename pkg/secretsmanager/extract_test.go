@@ -0,0 +1,45 @@
+package secretsmanager
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractField(t *testing.T) {
+	secret := `{"user":"admin","port":5432,"a/b":"slash","nested":{"hosts":["h1","h2"],"tls":{"on":true}}}`
+
+	tests := []struct {
+		key, pointer string
+		want         string
+		err          error
+	}{
+		{key: "user", want: "admin"},
+		{key: "port", want: "5432"},
+		{key: "a/b", want: "slash"},
+		{key: "nested", want: `{"hosts":["h1","h2"],"tls":{"on":true}}`},
+		{pointer: "/nested/hosts/1", want: "h2"},
+		{pointer: "/nested/tls/on", want: "true"},
+		{pointer: "/a~1b", want: "slash"},
+		{key: "missing", err: errKeyNotFound},
+		{pointer: "/nested/hosts/5", err: errKeyNotFound},
+		{pointer: "/user/deeper", err: errKeyNotFound},
+		{pointer: "nested", err: errBadPointer},
+	}
+
+	for _, tt := range tests {
+		got, err := extractField(secret, tt.key, tt.pointer)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("key=%q pointer=%q: got error %v want %v", tt.key, tt.pointer, err, tt.err)
+			continue
+		}
+		if err == nil && string(got) != tt.want {
+			t.Errorf("key=%q pointer=%q: got %q want %q", tt.key, tt.pointer, got, tt.want)
+		}
+	}
+}
+
+func TestExtractField_NotJSON(t *testing.T) {
+	if _, err := extractField("plain", "user", ""); !errors.Is(err, errNotJSON) {
+		t.Errorf("got %v want %v", err, errNotJSON)
+	}
+}
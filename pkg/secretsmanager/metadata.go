@@ -0,0 +1,195 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// ListSecrets returns the metadata of every secret matching filters,
+// following all result pages.
+func ListSecrets(ctx context.Context, svc SecretsManagerAPI, filters []types.Filter, includePlannedDeletion bool) ([]types.SecretListEntry, error) {
+	paginator := secretsmanager.NewListSecretsPaginator(svc, &secretsmanager.ListSecretsInput{
+		Filters:                filters,
+		IncludePlannedDeletion: aws.Bool(includePlannedDeletion),
+	})
+
+	var secrets []types.SecretListEntry
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, page.SecretList...)
+	}
+	return secrets, nil
+}
+
+func DescribeSecret(ctx context.Context, svc SecretsManagerAPI, name string) (*secretsmanager.DescribeSecretOutput, error) {
+	return svc.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(name),
+	})
+}
+
+type rotationRules struct {
+	AutomaticallyAfterDays int64  `json:"automatically_after_days,omitempty"`
+	Duration               string `json:"duration,omitempty"`
+	ScheduleExpression     string `json:"schedule_expression,omitempty"`
+}
+
+type secretMetadata struct {
+	Name              string              `json:"name"`
+	ARN               string              `json:"arn"`
+	Description       string              `json:"description,omitempty"`
+	KmsKeyID          string              `json:"kms_key_id,omitempty"`
+	RotationEnabled   bool                `json:"rotation_enabled"`
+	RotationLambdaARN string              `json:"rotation_lambda_arn,omitempty"`
+	RotationRules     *rotationRules      `json:"rotation_rules,omitempty"`
+	CreatedDate       *time.Time          `json:"created_date,omitempty"`
+	LastChangedDate   *time.Time          `json:"last_changed_date,omitempty"`
+	LastAccessedDate  *time.Time          `json:"last_accessed_date,omitempty"`
+	LastRotatedDate   *time.Time          `json:"last_rotated_date,omitempty"`
+	NextRotationDate  *time.Time          `json:"next_rotation_date,omitempty"`
+	DeletedDate       *time.Time          `json:"deleted_date,omitempty"`
+	VersionStages     map[string][]string `json:"version_stages"`
+	Tags              map[string]string   `json:"tags"`
+}
+
+func newSecretMetadata(e types.SecretListEntry) secretMetadata {
+	m := secretMetadata{
+		Name:              aws.ToString(e.Name),
+		ARN:               aws.ToString(e.ARN),
+		Description:       aws.ToString(e.Description),
+		KmsKeyID:          aws.ToString(e.KmsKeyId),
+		RotationEnabled:   aws.ToBool(e.RotationEnabled),
+		RotationLambdaARN: aws.ToString(e.RotationLambdaARN),
+		CreatedDate:       e.CreatedDate,
+		LastChangedDate:   e.LastChangedDate,
+		LastAccessedDate:  e.LastAccessedDate,
+		LastRotatedDate:   e.LastRotatedDate,
+		NextRotationDate:  e.NextRotationDate,
+		DeletedDate:       e.DeletedDate,
+		VersionStages:     e.SecretVersionsToStages,
+		Tags:              make(map[string]string, len(e.Tags)),
+	}
+	if m.VersionStages == nil {
+		m.VersionStages = map[string][]string{}
+	}
+	if rules := e.RotationRules; rules != nil {
+		m.RotationRules = &rotationRules{
+			AutomaticallyAfterDays: aws.ToInt64(rules.AutomaticallyAfterDays),
+			Duration:               aws.ToString(rules.Duration),
+			ScheduleExpression:     aws.ToString(rules.ScheduleExpression),
+		}
+	}
+	for _, tag := range e.Tags {
+		m.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return m
+}
+
+// HandleSecretsList lists secret metadata, never values. Repeated 'name',
+// 'description', 'tag_key' and 'tag_value' query parameters filter the result;
+// 'include_planned_deletion=true' also returns secrets scheduled for deletion.
+func HandleSecretsList(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	var filters []types.Filter
+	for _, f := range []struct {
+		param string
+		key   types.FilterNameStringType
+	}{
+		{"name", types.FilterNameStringTypeName},
+		{"description", types.FilterNameStringTypeDescription},
+		{"tag_key", types.FilterNameStringTypeTagKey},
+		{"tag_value", types.FilterNameStringTypeTagValue},
+	} {
+		if values := query[f.param]; len(values) > 0 {
+			filters = append(filters, types.Filter{Key: f.key, Values: values})
+		}
+	}
+
+	includeDeleted := false
+	if v := query.Get("include_planned_deletion"); v != "" {
+		var err error
+		if includeDeleted, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Parameter 'include_planned_deletion' must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	secrets, err := ListSecrets(ctx, svc, filters, includeDeleted)
+	if err != nil {
+		slog.Error("failed to list secrets", "error", err)
+		http.Error(w, "Error listing secrets", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]secretMetadata, 0, len(secrets))
+	for _, s := range secrets {
+		result = append(result, newSecretMetadata(s))
+	}
+	writeJSON(w, result)
+}
+
+// HandleSecretsDescribe serves the metadata of a single secret, including its
+// rotation state and version stages.
+func HandleSecretsDescribe(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	out, err := DescribeSecret(ctx, svc, name)
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			http.Error(w, "Secret not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to describe secret", "name", name, "error", err)
+		http.Error(w, "Error describing secret", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, newSecretMetadata(types.SecretListEntry{
+		Name:                   out.Name,
+		ARN:                    out.ARN,
+		Description:            out.Description,
+		KmsKeyId:               out.KmsKeyId,
+		RotationEnabled:        out.RotationEnabled,
+		RotationLambdaARN:      out.RotationLambdaARN,
+		RotationRules:          out.RotationRules,
+		CreatedDate:            out.CreatedDate,
+		LastChangedDate:        out.LastChangedDate,
+		LastAccessedDate:       out.LastAccessedDate,
+		LastRotatedDate:        out.LastRotatedDate,
+		NextRotationDate:       out.NextRotationDate,
+		DeletedDate:            out.DeletedDate,
+		SecretVersionsToStages: out.VersionIdsToStages,
+		Tags:                   out.Tags,
+	}))
+}
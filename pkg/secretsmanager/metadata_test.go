@@ -0,0 +1,85 @@
+package secretsmanager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+func TestHandleSecretsList(t *testing.T) {
+	mock := &mockSM{listPages: map[string]secretsmanager.ListSecretsOutput{
+		"": {
+			SecretList: []types.SecretListEntry{{Name: aws.String("app/a"), Tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("core")}}}},
+			NextToken:  aws.String("next"),
+		},
+		"next": {
+			SecretList: []types.SecretListEntry{{Name: aws.String("app/b"), RotationEnabled: aws.Bool(true)}},
+		},
+	}}
+
+	req := httptest.NewRequest("GET", "/secrets/list?name=app/&tag_key=team", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretsList(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if len(mock.listFilters) != 2 {
+		t.Errorf("got filters %+v want name and tag-key", mock.listFilters)
+	}
+	var got []secretMetadata
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(got) != 2 || got[0].Tags["team"] != "core" || !got[1].RotationEnabled {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestHandleSecretsDescribe(t *testing.T) {
+	rotated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock := &mockSM{describe: secretsmanager.DescribeSecretOutput{
+		Name:               aws.String("app/db"),
+		RotationEnabled:    aws.Bool(true),
+		RotationRules:      &types.RotationRulesType{AutomaticallyAfterDays: aws.Int64(30)},
+		LastRotatedDate:    &rotated,
+		VersionIdsToStages: map[string][]string{"v1": {"AWSCURRENT"}},
+	}}
+
+	req := httptest.NewRequest("GET", "/secrets/describe?name=app/db", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretsDescribe(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	var got secretMetadata
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if got.RotationRules == nil || got.RotationRules.AutomaticallyAfterDays != 30 {
+		t.Errorf("got rotation rules %+v", got.RotationRules)
+	}
+	if got.LastRotatedDate == nil || !got.LastRotatedDate.Equal(rotated) {
+		t.Errorf("got last rotated %v want %v", got.LastRotatedDate, rotated)
+	}
+	if got.VersionStages["v1"][0] != "AWSCURRENT" {
+		t.Errorf("got version stages %v", got.VersionStages)
+	}
+}
+
+func TestHandleSecretsDescribe_NotFound(t *testing.T) {
+	req := httptest.NewRequest("GET", "/secrets/describe?name=nope", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretsDescribe(rr, req, &mockSM{err: &types.ResourceNotFoundException{}})
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,144 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// GetRandomPassword generates a password server side. Unset fields of input
+// fall back to the Secrets Manager defaults: 32 characters drawn from all
+// character classes.
+func GetRandomPassword(ctx context.Context, svc SecretsManagerAPI, input *secretsmanager.GetRandomPasswordInput) (string, error) {
+	out, err := svc.GetRandomPassword(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.RandomPassword), nil
+}
+
+// storeSecretValue stores value as the new AWSCURRENT version of the secret,
+// creating the secret when it does not exist yet.
+func storeSecretValue(ctx context.Context, svc SecretsManagerAPI, name, value string) (secretResponse, error) {
+	req := SecretRequest{Name: name, SecretString: value}
+	put, err := PutSecretValue(ctx, svc, req)
+	if err == nil {
+		return secretResponse{ARN: aws.ToString(put.ARN), Name: aws.ToString(put.Name), VersionID: aws.ToString(put.VersionId)}, nil
+	}
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		return secretResponse{}, err
+	}
+
+	created, err := CreateSecret(ctx, svc, req)
+	if err != nil {
+		return secretResponse{}, err
+	}
+	return secretResponse{ARN: aws.ToString(created.ARN), Name: aws.ToString(created.Name), VersionID: aws.ToString(created.VersionId)}, nil
+}
+
+// passwordInput builds a GetRandomPasswordInput from the 'length',
+// 'exclude_characters', 'exclude_lowercase', 'exclude_uppercase',
+// 'exclude_numbers', 'exclude_punctuation', 'include_space' and
+// 'require_each_included_type' parameters.
+func passwordInput(r *http.Request) (*secretsmanager.GetRandomPasswordInput, error) {
+	input := &secretsmanager.GetRandomPasswordInput{}
+	if v := r.FormValue("length"); v != "" {
+		length, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || length < 1 || length > 4096 {
+			return nil, errors.New("Parameter 'length' must be between 1 and 4096")
+		}
+		input.PasswordLength = aws.Int64(length)
+	}
+	if v := r.FormValue("exclude_characters"); v != "" {
+		input.ExcludeCharacters = aws.String(v)
+	}
+
+	for _, flag := range []struct {
+		name  string
+		field **bool
+	}{
+		{"exclude_lowercase", &input.ExcludeLowercase},
+		{"exclude_uppercase", &input.ExcludeUppercase},
+		{"exclude_numbers", &input.ExcludeNumbers},
+		{"exclude_punctuation", &input.ExcludePunctuation},
+		{"include_space", &input.IncludeSpace},
+		{"require_each_included_type", &input.RequireEachIncludedType},
+	} {
+		v := r.FormValue(flag.name)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("Parameter '%s' must be a boolean", flag.name)
+		}
+		*flag.field = aws.Bool(b)
+	}
+	return input, nil
+}
+
+// HandleRandomPassword returns a generated password as plain text. With
+// 'store_as', which is only accepted on POST, the password is instead stored
+// in that secret, created if needed, and only the secret's identifiers are
+// returned so the value never reaches the caller.
+func HandleRandomPassword(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+	// A retried or prefetched GET must not silently rotate a credential.
+	if r.Method == http.MethodGet && r.URL.Query().Has("store_as") {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Parameter 'store_as' requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	input, err := passwordInput(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	password, err := GetRandomPassword(ctx, svc, input)
+	if err != nil {
+		var invalid *types.InvalidParameterException
+		if errors.As(err, &invalid) {
+			http.Error(w, invalid.ErrorMessage(), http.StatusBadRequest)
+			return
+		}
+		slog.Error("failed to generate password", "error", err)
+		http.Error(w, "Error generating password", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	name := r.FormValue("store_as")
+	if name == "" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(password))
+		return
+	}
+
+	result, err := storeSecretValue(ctx, svc, name, password)
+	if err != nil {
+		slog.Error("failed to store generated password", "name", name, "error", err)
+		http.Error(w, "Error storing generated password", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("generated password stored", "name", name, "version", result.VersionID)
+	writeJSON(w, result)
+}
@@ -0,0 +1,87 @@
+package secretsmanager
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+func TestHandleRandomPassword(t *testing.T) {
+	mock := &mockSM{}
+	req := httptest.NewRequest("GET", "/secrets/random-password?length=20&exclude_punctuation=true&exclude_characters=abc", nil)
+	rr := httptest.NewRecorder()
+	HandleRandomPassword(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if rr.Body.String() != "r4nd0m" {
+		t.Errorf("got %q want r4nd0m", rr.Body.String())
+	}
+	in := mock.pwInput
+	if aws.ToInt64(in.PasswordLength) != 20 || !aws.ToBool(in.ExcludePunctuation) || aws.ToString(in.ExcludeCharacters) != "abc" {
+		t.Errorf("got input %+v", in)
+	}
+	if in.ExcludeNumbers != nil {
+		t.Errorf("got exclude numbers %v want unset", *in.ExcludeNumbers)
+	}
+}
+
+func TestHandleRandomPassword_StoreAsExisting(t *testing.T) {
+	mock := &mockSM{}
+	req := httptest.NewRequest("POST", "/secrets/random-password?store_as=app/pw", nil)
+	rr := httptest.NewRecorder()
+	HandleRandomPassword(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if aws.ToString(mock.putInput.SecretString) != "r4nd0m" || mock.createInput != nil {
+		t.Errorf("got put %+v create %+v", mock.putInput, mock.createInput)
+	}
+	if strings.Contains(rr.Body.String(), "r4nd0m") {
+		t.Errorf("stored password must not be returned: %s", rr.Body.String())
+	}
+}
+
+func TestHandleRandomPassword_StoreAsNew(t *testing.T) {
+	mock := &mockSM{putErr: &types.ResourceNotFoundException{}}
+	req := httptest.NewRequest("POST", "/secrets/random-password?store_as=app/pw", nil)
+	rr := httptest.NewRecorder()
+	HandleRandomPassword(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if mock.createInput == nil || aws.ToString(mock.createInput.SecretString) != "r4nd0m" {
+		t.Errorf("got create %+v want secret created with password", mock.createInput)
+	}
+}
+
+func TestHandleRandomPassword_StoreAsRequiresPost(t *testing.T) {
+	mock := &mockSM{}
+	req := httptest.NewRequest("GET", "/secrets/random-password?store_as=app/pw", nil)
+	rr := httptest.NewRecorder()
+	HandleRandomPassword(rr, req, mock)
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("got %d want %d", rr.Code, http.StatusMethodNotAllowed)
+	}
+	if mock.pwInput != nil || mock.putInput != nil || mock.createInput != nil {
+		t.Errorf("GET with store_as must not generate or store a password")
+	}
+}
+
+func TestHandleRandomPassword_InvalidLength(t *testing.T) {
+	req := httptest.NewRequest("GET", "/secrets/random-password?length=0", nil)
+	rr := httptest.NewRecorder()
+	HandleRandomPassword(rr, req, &mockSM{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
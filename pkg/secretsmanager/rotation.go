@@ -0,0 +1,153 @@
+package secretsmanager
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// RotationRequest configures a RotateSecret call. Zero values leave the
+// secret's existing rotation configuration untouched.
+type RotationRequest struct {
+	Name                   string
+	RotateImmediately      *bool
+	LambdaARN              string
+	AutomaticallyAfterDays int64
+	ScheduleExpression     string
+	Duration               string
+}
+
+func RotateSecret(ctx context.Context, svc SecretsManagerAPI, req RotationRequest) (*secretsmanager.RotateSecretOutput, error) {
+	input := &secretsmanager.RotateSecretInput{
+		SecretId:          aws.String(req.Name),
+		RotateImmediately: req.RotateImmediately,
+	}
+	if req.LambdaARN != "" {
+		input.RotationLambdaARN = aws.String(req.LambdaARN)
+	}
+	if req.AutomaticallyAfterDays > 0 || req.ScheduleExpression != "" || req.Duration != "" {
+		input.RotationRules = &types.RotationRulesType{}
+		if req.AutomaticallyAfterDays > 0 {
+			input.RotationRules.AutomaticallyAfterDays = aws.Int64(req.AutomaticallyAfterDays)
+		}
+		if req.ScheduleExpression != "" {
+			input.RotationRules.ScheduleExpression = aws.String(req.ScheduleExpression)
+		}
+		if req.Duration != "" {
+			input.RotationRules.Duration = aws.String(req.Duration)
+		}
+	}
+	return svc.RotateSecret(ctx, input)
+}
+
+// CancelRotateSecret turns off automatic rotation and abandons a rotation in
+// progress.
+func CancelRotateSecret(ctx context.Context, svc SecretsManagerAPI, name string) (*secretsmanager.CancelRotateSecretOutput, error) {
+	return svc.CancelRotateSecret(ctx, &secretsmanager.CancelRotateSecretInput{
+		SecretId: aws.String(name),
+	})
+}
+
+// HandleSecretRotate starts a rotation of the secret given by 'name'. The
+// optional 'rotate_immediately', 'lambda_arn', 'automatically_after_days',
+// 'schedule_expression' and 'duration' parameters change how and when the
+// secret is rotated. Progress can be followed through /secrets/describe.
+func HandleSecretRotate(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := RotationRequest{
+		Name:               r.FormValue("name"),
+		LambdaARN:          r.FormValue("lambda_arn"),
+		ScheduleExpression: r.FormValue("schedule_expression"),
+		Duration:           r.FormValue("duration"),
+	}
+	if req.Name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+	if v := r.FormValue("rotate_immediately"); v != "" {
+		immediately, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "Parameter 'rotate_immediately' must be a boolean", http.StatusBadRequest)
+			return
+		}
+		req.RotateImmediately = aws.Bool(immediately)
+	}
+	if v := r.FormValue("automatically_after_days"); v != "" {
+		days, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || days < 1 || days > 1000 {
+			http.Error(w, "Parameter 'automatically_after_days' must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		req.AutomaticallyAfterDays = days
+	}
+	if req.AutomaticallyAfterDays > 0 && req.ScheduleExpression != "" {
+		http.Error(w, "Parameters 'automatically_after_days' and 'schedule_expression' are mutually exclusive", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	out, err := RotateSecret(ctx, svc, req)
+	if err != nil {
+		writeRotationError(w, "failed to rotate secret", req.Name, err)
+		return
+	}
+
+	slog.Info("secret rotation started", "name", req.Name, "version", aws.ToString(out.VersionId))
+	writeJSON(w, secretResponse{ARN: aws.ToString(out.ARN), Name: aws.ToString(out.Name), VersionID: aws.ToString(out.VersionId)})
+}
+
+// HandleSecretCancelRotation cancels rotation for the secret given by 'name'.
+func HandleSecretCancelRotation(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	out, err := CancelRotateSecret(ctx, svc, name)
+	if err != nil {
+		writeRotationError(w, "failed to cancel secret rotation", name, err)
+		return
+	}
+
+	slog.Info("secret rotation cancelled", "name", name)
+	writeJSON(w, secretResponse{ARN: aws.ToString(out.ARN), Name: aws.ToString(out.Name), VersionID: aws.ToString(out.VersionId)})
+}
+
+// writeRotationError reports unknown secrets as 404 and requests Secrets
+// Manager rejects in the secret's current state, such as a rotation already
+// in progress, as 409.
+func writeRotationError(w http.ResponseWriter, msg, name string, err error) {
+	var notFound *types.ResourceNotFoundException
+	var invalid *types.InvalidRequestException
+	switch {
+	case errors.As(err, &notFound):
+		http.Error(w, "Secret not found", http.StatusNotFound)
+	case errors.As(err, &invalid):
+		http.Error(w, invalid.ErrorMessage(), http.StatusConflict)
+	default:
+		slog.Error(msg, "name", name, "error", err)
+		http.Error(w, "Error updating secret rotation", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,73 @@
+package secretsmanager
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+func TestHandleSecretRotate(t *testing.T) {
+	mock := &mockSM{}
+	req := httptest.NewRequest("POST", "/secrets/rotate?name=app/db&rotate_immediately=false&schedule_expression=rate(10+days)", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretRotate(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	in := mock.rotateInput
+	if in.RotateImmediately == nil || *in.RotateImmediately {
+		t.Errorf("got rotate immediately %v want false", in.RotateImmediately)
+	}
+	if in.RotationRules == nil || aws.ToString(in.RotationRules.ScheduleExpression) != "rate(10 days)" {
+		t.Errorf("got rotation rules %+v", in.RotationRules)
+	}
+}
+
+func TestHandleSecretRotate_Defaults(t *testing.T) {
+	mock := &mockSM{}
+	req := httptest.NewRequest("POST", "/secrets/rotate?name=app/db", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretRotate(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if mock.rotateInput.RotateImmediately != nil || mock.rotateInput.RotationRules != nil {
+		t.Errorf("got input %+v want only the secret ID", mock.rotateInput)
+	}
+}
+
+func TestHandleSecretRotate_InProgress(t *testing.T) {
+	mock := &mockSM{err: &types.InvalidRequestException{Message: aws.String("rotation in progress")}}
+	req := httptest.NewRequest("POST", "/secrets/rotate?name=app/db", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretRotate(rr, req, mock)
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("got %d want %d", rr.Code, http.StatusConflict)
+	}
+}
+
+func TestHandleSecretRotate_InvalidSchedule(t *testing.T) {
+	req := httptest.NewRequest("POST", "/secrets/rotate?name=app/db&automatically_after_days=30&schedule_expression=rate(1+days)", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretRotate(rr, req, &mockSM{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleSecretCancelRotation(t *testing.T) {
+	req := httptest.NewRequest("POST", "/secrets/cancel-rotation?name=app/db", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretCancelRotation(rr, req, &mockSM{})
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("got %d want %d", rr.Code, http.StatusOK)
+	}
+}
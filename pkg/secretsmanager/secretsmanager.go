@@ -2,54 +2,198 @@ package secretsmanager
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 )
 
 // SecretsManagerAPI defines the interface for Secrets Manager operations.
 type SecretsManagerAPI interface {
 	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+	CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+	PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error)
+	DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+	RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error)
+	ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
+	DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error)
+	BatchGetSecretValue(ctx context.Context, params *secretsmanager.BatchGetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.BatchGetSecretValueOutput, error)
+	RotateSecret(ctx context.Context, params *secretsmanager.RotateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RotateSecretOutput, error)
+	CancelRotateSecret(ctx context.Context, params *secretsmanager.CancelRotateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CancelRotateSecretOutput, error)
+	GetRandomPassword(ctx context.Context, params *secretsmanager.GetRandomPasswordInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetRandomPasswordOutput, error)
 }
 
-func GetSecret(ctx context.Context, svc SecretsManagerAPI, name string) (*secretsmanager.GetSecretValueOutput, error) {
-	return svc.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+// GetSecret reads a secret value. Without versionID or versionStage the
+// AWSCURRENT version is returned.
+func GetSecret(ctx context.Context, svc SecretsManagerAPI, name, versionID, versionStage string) (*secretsmanager.GetSecretValueOutput, error) {
+	input := &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(name),
-	})
+	}
+	if versionID != "" {
+		input.VersionId = aws.String(versionID)
+	}
+	if versionStage != "" {
+		input.VersionStage = aws.String(versionStage)
+	}
+	return svc.GetSecretValue(ctx, input)
 }
 
 func HandleSecrets(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
-	if r.Method != http.MethodGet {
+	switch r.Method {
+	case http.MethodGet:
+		handleGetSecret(w, r, svc)
+	case http.MethodPost:
+		handlePostSecret(w, r, svc)
+	case http.MethodPut:
+		handlePutSecret(w, r, svc)
+	case http.MethodDelete:
+		handleDeleteSecret(w, r, svc)
+	default:
 		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+	}
+}
+
+func handleGetSecret(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	if isBatchRequest(r) {
+		handleBatchGetSecret(w, r, svc)
 		return
 	}
 
-	name := r.URL.Query().Get("name")
+	query := r.URL.Query()
+	name := query.Get("name")
 	if name == "" {
 		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
 		return
 	}
 
+	key, pointer := query.Get("key"), query.Get("pointer")
+	if key != "" && pointer != "" {
+		http.Error(w, "Parameters 'key' and 'pointer' are mutually exclusive", http.StatusBadRequest)
+		return
+	}
+
+	encoding := query.Get("encoding")
+	switch encoding {
+	case "":
+		encoding = encodingRaw
+	case encodingRaw, encodingBase64, encodingHex:
+	default:
+		http.Error(w, "Parameter 'encoding' must be 'raw', 'base64' or 'hex'", http.StatusBadRequest)
+		return
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" {
+		http.Error(w, "Parameter 'format' must be 'json'", http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	result, err := GetSecret(ctx, svc, name)
+	result, err := GetSecret(ctx, svc, name, query.Get("version_id"), query.Get("version_stage"))
 	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			http.Error(w, "Secret or version not found", http.StatusNotFound)
+			return
+		}
 		slog.Error("failed to fetch secret", "error", err)
 		http.Error(w, "Error fetching secret", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/plain")
-	if result.SecretString != nil {
-		w.Write([]byte(*result.SecretString))
-	} else if result.SecretBinary != nil {
+	var value []byte
+	binary := false
+	switch {
+	case key != "" || pointer != "":
+		if result.SecretString == nil {
+			http.Error(w, "Field extraction requires a string secret", http.StatusBadRequest)
+			return
+		}
+		value, err = extractField(*result.SecretString, key, pointer)
+		switch {
+		case errors.Is(err, errKeyNotFound):
+			http.Error(w, "Key not found in secret", http.StatusNotFound)
+			return
+		case errors.Is(err, errNotJSON), errors.Is(err, errBadPointer):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			slog.Error("failed to extract secret field", "error", err)
+			http.Error(w, "Error extracting secret field", http.StatusInternalServerError)
+			return
+		}
+	case result.SecretString != nil:
+		value = []byte(*result.SecretString)
+	case result.SecretBinary != nil:
+		value = result.SecretBinary
+		binary = true
+	default:
+		http.Error(w, "Secret has no value", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Secret-Version-Id", aws.ToString(result.VersionId))
+	w.Header().Set("X-Secret-Version-Stages", strings.Join(result.VersionStages, ","))
+
+	if format == "json" {
+		// Raw bytes cannot be embedded in JSON, so binary values default to base64.
+		if binary && encoding == encodingRaw {
+			encoding = encodingBase64
+		}
+		writeJSON(w, secretEnvelope{
+			Name:          aws.ToString(result.Name),
+			ARN:           aws.ToString(result.ARN),
+			VersionID:     aws.ToString(result.VersionId),
+			VersionStages: result.VersionStages,
+			CreatedDate:   result.CreatedDate,
+			Binary:        binary,
+			Encoding:      encoding,
+			Value:         string(encodeValue(value, encoding)),
+		})
+		return
+	}
+
+	if binary && encoding == encodingRaw {
 		w.Header().Set("Content-Type", "application/octet-stream")
-		w.Write(result.SecretBinary)
 	} else {
-		http.Error(w, "Secret has no value", http.StatusInternalServerError)
+		w.Header().Set("Content-Type", "text/plain")
+	}
+	w.Write(encodeValue(value, encoding))
+}
+
+// secretEnvelope is the response of GET /secrets with 'format=json'.
+type secretEnvelope struct {
+	Name          string     `json:"name"`
+	ARN           string     `json:"arn"`
+	VersionID     string     `json:"version_id"`
+	VersionStages []string   `json:"version_stages"`
+	CreatedDate   *time.Time `json:"created_date,omitempty"`
+	Binary        bool       `json:"binary"`
+	Encoding      string     `json:"encoding"`
+	Value         string     `json:"value"`
+}
+
+const (
+	encodingRaw    = "raw"
+	encodingBase64 = "base64"
+	encodingHex    = "hex"
+)
+
+func encodeValue(value []byte, encoding string) []byte {
+	switch encoding {
+	case encodingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(value))
+	case encodingHex:
+		return []byte(hex.EncodeToString(value))
 	}
+	return value
 }
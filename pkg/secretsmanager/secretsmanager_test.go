@@ -2,24 +2,117 @@ package secretsmanager
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 )
 
 type mockSM struct {
-	resp secretsmanager.GetSecretValueOutput
-	err  error
+	resp        secretsmanager.GetSecretValueOutput
+	getInput    *secretsmanager.GetSecretValueInput
+	createInput *secretsmanager.CreateSecretInput
+	putInput    *secretsmanager.PutSecretValueInput
+	updateInput *secretsmanager.UpdateSecretInput
+	deleteInput *secretsmanager.DeleteSecretInput
+	listPages   map[string]secretsmanager.ListSecretsOutput
+	listFilters []types.Filter
+	describe    secretsmanager.DescribeSecretOutput
+	batchCalls  []*secretsmanager.BatchGetSecretValueInput
+	rotateInput *secretsmanager.RotateSecretInput
+	pwInput     *secretsmanager.GetRandomPasswordInput
+	putErr      error
+	err         error
 }
 
 func (m *mockSM) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	m.getInput = params
 	return &m.resp, m.err
 }
 
+func (m *mockSM) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	m.createInput = params
+	return &secretsmanager.CreateSecretOutput{Name: params.Name, VersionId: aws.String("v1")}, m.err
+}
+
+func (m *mockSM) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	m.putInput = params
+	if m.putErr != nil {
+		return nil, m.putErr
+	}
+	return &secretsmanager.PutSecretValueOutput{Name: params.SecretId, VersionId: aws.String("v2"), VersionStages: params.VersionStages}, m.err
+}
+
+func (m *mockSM) UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error) {
+	m.updateInput = params
+	return &secretsmanager.UpdateSecretOutput{Name: params.SecretId}, m.err
+}
+
+func (m *mockSM) DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	m.deleteInput = params
+	return &secretsmanager.DeleteSecretOutput{Name: params.SecretId}, m.err
+}
+
+func (m *mockSM) RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error) {
+	return &secretsmanager.RestoreSecretOutput{Name: params.SecretId}, m.err
+}
+
+func (m *mockSM) ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	m.listFilters = params.Filters
+	page := m.listPages[aws.ToString(params.NextToken)]
+	return &page, m.err
+}
+
+func (m *mockSM) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	return &m.describe, m.err
+}
+
+// BatchGetSecretValue returns every requested ID as a secret, except IDs
+// starting with "missing" which are reported as errors.
+func (m *mockSM) BatchGetSecretValue(ctx context.Context, params *secretsmanager.BatchGetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.BatchGetSecretValueOutput, error) {
+	m.batchCalls = append(m.batchCalls, params)
+	out := &secretsmanager.BatchGetSecretValueOutput{}
+	for _, id := range params.SecretIdList {
+		if strings.HasPrefix(id, "missing") {
+			out.Errors = append(out.Errors, types.APIErrorType{SecretId: aws.String(id), ErrorCode: aws.String("ResourceNotFoundException")})
+			continue
+		}
+		if strings.HasPrefix(id, "arn:") {
+			out.SecretValues = append(out.SecretValues, types.SecretValueEntry{
+				Name:         aws.String("by-arn"),
+				ARN:          aws.String("arn:aws:secretsmanager:eu-central-1:123456789012:secret:by-arn-AbCdEf"),
+				SecretString: aws.String("v-arn"),
+			})
+			continue
+		}
+		out.SecretValues = append(out.SecretValues, types.SecretValueEntry{Name: aws.String(id), SecretString: aws.String("v-" + id)})
+	}
+	if len(params.Filters) > 0 {
+		out.SecretValues = append(out.SecretValues, types.SecretValueEntry{Name: aws.String("filtered"), SecretBinary: []byte{1, 2, 3}})
+	}
+	return out, m.err
+}
+
+func (m *mockSM) RotateSecret(ctx context.Context, params *secretsmanager.RotateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RotateSecretOutput, error) {
+	m.rotateInput = params
+	return &secretsmanager.RotateSecretOutput{Name: params.SecretId, VersionId: aws.String("v-new")}, m.err
+}
+
+func (m *mockSM) CancelRotateSecret(ctx context.Context, params *secretsmanager.CancelRotateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CancelRotateSecretOutput, error) {
+	return &secretsmanager.CancelRotateSecretOutput{Name: params.SecretId}, m.err
+}
+
+func (m *mockSM) GetRandomPassword(ctx context.Context, params *secretsmanager.GetRandomPasswordInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetRandomPasswordOutput, error) {
+	m.pwInput = params
+	return &secretsmanager.GetRandomPasswordOutput{RandomPassword: aws.String("r4nd0m")}, m.err
+}
+
 func TestHandleSecrets(t *testing.T) {
 	mock := &mockSM{resp: secretsmanager.GetSecretValueOutput{
 		SecretString: aws.String(`{"user":"admin","pass":"secret"}`),
@@ -37,6 +130,37 @@ func TestHandleSecrets(t *testing.T) {
 	}
 }
 
+func TestHandleSecrets_Key(t *testing.T) {
+	mock := &mockSM{resp: secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"admin","password":"s3cr\"et"}`),
+	}}
+
+	req := httptest.NewRequest("GET", "/secrets?name=db&key=password", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if rr.Body.String() != `s3cr"et` {
+		t.Errorf("got %q want raw password", rr.Body.String())
+	}
+}
+
+func TestHandleSecrets_KeyNotFound(t *testing.T) {
+	mock := &mockSM{resp: secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"username":"admin"}`),
+	}}
+
+	req := httptest.NewRequest("GET", "/secrets?name=db&pointer=/password", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
 func TestHandleSecrets_Binary(t *testing.T) {
 	mock := &mockSM{resp: secretsmanager.GetSecretValueOutput{
 		SecretBinary: []byte{0x01, 0x02, 0x03},
@@ -54,6 +178,102 @@ func TestHandleSecrets_Binary(t *testing.T) {
 	}
 }
 
+func TestHandleSecrets_VersionStage(t *testing.T) {
+	mock := &mockSM{resp: secretsmanager.GetSecretValueOutput{
+		SecretString:  aws.String("pending"),
+		VersionId:     aws.String("v2"),
+		VersionStages: []string{"AWSPENDING"},
+	}}
+
+	req := httptest.NewRequest("GET", "/secrets?name=my-secret&version_stage=AWSPENDING", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if aws.ToString(mock.getInput.VersionStage) != "AWSPENDING" || mock.getInput.VersionId != nil {
+		t.Errorf("got input %+v", mock.getInput)
+	}
+	if got := rr.Header().Get("X-Secret-Version-Id"); got != "v2" {
+		t.Errorf("got X-Secret-Version-Id %q want v2", got)
+	}
+	if got := rr.Header().Get("X-Secret-Version-Stages"); got != "AWSPENDING" {
+		t.Errorf("got X-Secret-Version-Stages %q want AWSPENDING", got)
+	}
+}
+
+func TestHandleSecrets_NotFound(t *testing.T) {
+	mock := &mockSM{err: &types.ResourceNotFoundException{}}
+
+	req := httptest.NewRequest("GET", "/secrets?name=my-secret&version_id=nope", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleSecrets_Encoding(t *testing.T) {
+	mock := &mockSM{resp: secretsmanager.GetSecretValueOutput{
+		SecretBinary: []byte{0xde, 0xad, 0xbe, 0xef},
+	}}
+
+	for encoding, want := range map[string]string{"base64": "3q2+7w==", "hex": "deadbeef"} {
+		req := httptest.NewRequest("GET", "/secrets?name=bin&encoding="+encoding, nil)
+		rr := httptest.NewRecorder()
+		HandleSecrets(rr, req, mock)
+
+		if rr.Body.String() != want {
+			t.Errorf("%s: got %q want %q", encoding, rr.Body.String(), want)
+		}
+		if rr.Header().Get("Content-Type") != "text/plain" {
+			t.Errorf("%s: got Content-Type %q want text/plain", encoding, rr.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestHandleSecrets_JSONEnvelope(t *testing.T) {
+	mock := &mockSM{resp: secretsmanager.GetSecretValueOutput{
+		Name:          aws.String("bin"),
+		ARN:           aws.String("arn:bin"),
+		VersionId:     aws.String("v1"),
+		VersionStages: []string{"AWSCURRENT"},
+		SecretBinary:  []byte{1, 2, 3},
+	}}
+
+	req := httptest.NewRequest("GET", "/secrets?name=bin&format=json", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var got secretEnvelope
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if got.Name != "bin" || got.ARN != "arn:bin" || got.VersionID != "v1" || !got.Binary {
+		t.Errorf("got %+v", got)
+	}
+	if got.Encoding != "base64" || got.Value != "AQID" {
+		t.Errorf("got value %q encoding %q want base64 AQID", got.Value, got.Encoding)
+	}
+}
+
+func TestHandleSecrets_InvalidEncoding(t *testing.T) {
+	for _, query := range []string{"encoding=rot13", "format=xml"} {
+		req := httptest.NewRequest("GET", "/secrets?name=s&"+query, nil)
+		rr := httptest.NewRecorder()
+		HandleSecrets(rr, req, &mockSM{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
 func TestHandleSecrets_MissingName(t *testing.T) {
 	req := httptest.NewRequest("GET", "/secrets", nil)
 	rr := httptest.NewRecorder()
@@ -77,7 +297,7 @@ func TestHandleSecrets_AWSError(t *testing.T) {
 }
 
 func TestHandleSecrets_MethodNotAllowed(t *testing.T) {
-	req := httptest.NewRequest("POST", "/secrets", nil)
+	req := httptest.NewRequest("PATCH", "/secrets", nil)
 	rr := httptest.NewRecorder()
 	HandleSecrets(rr, req, &mockSM{})
 
@@ -85,3 +305,91 @@ func TestHandleSecrets_MethodNotAllowed(t *testing.T) {
 		t.Errorf("got %d want %d", rr.Code, http.StatusMethodNotAllowed)
 	}
 }
+
+func TestHandleSecrets_Batch(t *testing.T) {
+	mock := &mockSM{}
+	query := "name=missing"
+	for i := range 21 {
+		query += fmt.Sprintf("&name=s%d", i)
+	}
+
+	req := httptest.NewRequest("GET", "/secrets?"+query, nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if len(mock.batchCalls) != 2 || len(mock.batchCalls[0].SecretIdList) != 20 {
+		t.Errorf("got %d calls want chunks of 20", len(mock.batchCalls))
+	}
+	var got batchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(got.Secrets) != 21 || got.Secrets["s7"] != "v-s7" {
+		t.Errorf("got secrets %v", got.Secrets)
+	}
+	if len(got.Errors) != 1 || got.Errors[0].SecretID != "missing" || got.Errors[0].ErrorCode != "ResourceNotFoundException" {
+		t.Errorf("got errors %+v", got.Errors)
+	}
+}
+
+func TestHandleSecrets_BatchFilter(t *testing.T) {
+	mock := &mockSM{}
+
+	req := httptest.NewRequest("GET", "/secrets?filter=tag-key=app&filter=tag-key=team", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	filters := mock.batchCalls[0].Filters
+	if len(filters) != 1 || len(filters[0].Values) != 2 {
+		t.Errorf("got filters %+v want one tag-key filter with two values", filters)
+	}
+	if !strings.Contains(rr.Body.String(), `"filtered":"AQID"`) {
+		t.Errorf("got body %s want base64 binary value", rr.Body.String())
+	}
+}
+
+func TestHandleSecrets_BatchKeyedByRequestedID(t *testing.T) {
+	full := "arn:aws:secretsmanager:eu-central-1:123456789012:secret:by-arn-AbCdEf"
+	partial := "arn:aws:secretsmanager:eu-central-1:123456789012:secret:by-arn"
+	for _, id := range []string{full, partial} {
+		req := httptest.NewRequest("GET", "/secrets?batch=true&name="+id, nil)
+		rr := httptest.NewRecorder()
+		HandleSecrets(rr, req, &mockSM{})
+
+		var got batchResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to unmarshal: %v", err)
+		}
+		if got.Secrets[id] != "v-arn" {
+			t.Errorf("%s: got secrets %v want value keyed by requested ID", id, got.Secrets)
+		}
+	}
+}
+
+func TestHandleSecrets_BatchSingleSecretParams(t *testing.T) {
+	for _, param := range []string{"version_stage=AWSPENDING", "key=password", "encoding=hex", "format=json"} {
+		req := httptest.NewRequest("GET", "/secrets?name=a&name=b&"+param, nil)
+		rr := httptest.NewRecorder()
+		HandleSecrets(rr, req, &mockSM{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", param, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandleSecrets_BatchInvalidFilter(t *testing.T) {
+	req := httptest.NewRequest("GET", "/secrets?filter=color=blue", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, &mockSM{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,393 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// maxSecretSize is the largest secret value Secrets Manager accepts.
+const maxSecretSize = 65536
+
+// SecretRequest describes a secret write. It doubles as the JSON body accepted
+// by POST and PUT on /secrets; SecretBinary is base64 encoded in JSON.
+type SecretRequest struct {
+	Name          string            `json:"name"`
+	SecretString  string            `json:"secret_string"`
+	SecretBinary  []byte            `json:"secret_binary"`
+	KmsKeyID      string            `json:"kms_key_id"`
+	Description   string            `json:"description"`
+	Tags          map[string]string `json:"tags"`
+	VersionStages []string          `json:"version_stages"`
+}
+
+func (req SecretRequest) hasValue() bool {
+	return req.SecretString != "" || len(req.SecretBinary) > 0
+}
+
+func (req SecretRequest) hasMetadata() bool {
+	return req.KmsKeyID != "" || req.Description != ""
+}
+
+type secretResponse struct {
+	ARN           string   `json:"arn"`
+	Name          string   `json:"name"`
+	VersionID     string   `json:"version_id,omitempty"`
+	VersionStages []string `json:"version_stages,omitempty"`
+}
+
+func CreateSecret(ctx context.Context, svc SecretsManagerAPI, req SecretRequest) (*secretsmanager.CreateSecretOutput, error) {
+	input := &secretsmanager.CreateSecretInput{
+		Name:         aws.String(req.Name),
+		SecretBinary: req.SecretBinary,
+	}
+	if req.SecretString != "" {
+		input.SecretString = aws.String(req.SecretString)
+	}
+	if req.KmsKeyID != "" {
+		input.KmsKeyId = aws.String(req.KmsKeyID)
+	}
+	if req.Description != "" {
+		input.Description = aws.String(req.Description)
+	}
+	for key, value := range req.Tags {
+		input.Tags = append(input.Tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return svc.CreateSecret(ctx, input)
+}
+
+// PutSecretValue stores a new secret version. Without version stages the new
+// version becomes AWSCURRENT.
+func PutSecretValue(ctx context.Context, svc SecretsManagerAPI, req SecretRequest) (*secretsmanager.PutSecretValueOutput, error) {
+	input := &secretsmanager.PutSecretValueInput{
+		SecretId:      aws.String(req.Name),
+		SecretBinary:  req.SecretBinary,
+		VersionStages: req.VersionStages,
+	}
+	if req.SecretString != "" {
+		input.SecretString = aws.String(req.SecretString)
+	}
+	return svc.PutSecretValue(ctx, input)
+}
+
+// UpdateSecret changes the description or KMS key of a secret and, when a
+// value is given, stores it as the new AWSCURRENT version.
+func UpdateSecret(ctx context.Context, svc SecretsManagerAPI, req SecretRequest) (*secretsmanager.UpdateSecretOutput, error) {
+	input := &secretsmanager.UpdateSecretInput{
+		SecretId:     aws.String(req.Name),
+		SecretBinary: req.SecretBinary,
+	}
+	if req.SecretString != "" {
+		input.SecretString = aws.String(req.SecretString)
+	}
+	if req.KmsKeyID != "" {
+		input.KmsKeyId = aws.String(req.KmsKeyID)
+	}
+	if req.Description != "" {
+		input.Description = aws.String(req.Description)
+	}
+	return svc.UpdateSecret(ctx, input)
+}
+
+// DeleteSecret schedules a secret for deletion after recoveryWindowDays, or
+// deletes it immediately without any recovery window when force is set. A
+// zero window uses the Secrets Manager default of 30 days.
+func DeleteSecret(ctx context.Context, svc SecretsManagerAPI, name string, recoveryWindowDays int64, force bool) (*secretsmanager.DeleteSecretOutput, error) {
+	input := &secretsmanager.DeleteSecretInput{
+		SecretId: aws.String(name),
+	}
+	if force {
+		input.ForceDeleteWithoutRecovery = aws.Bool(true)
+	} else if recoveryWindowDays > 0 {
+		input.RecoveryWindowInDays = aws.Int64(recoveryWindowDays)
+	}
+	return svc.DeleteSecret(ctx, input)
+}
+
+// RestoreSecret cancels a scheduled deletion.
+func RestoreSecret(ctx context.Context, svc SecretsManagerAPI, name string) (*secretsmanager.RestoreSecretOutput, error) {
+	return svc.RestoreSecret(ctx, &secretsmanager.RestoreSecretInput{
+		SecretId: aws.String(name),
+	})
+}
+
+// parseSecretRequest reads a SecretRequest from a JSON body, from a raw
+// application/octet-stream body holding the binary value, or from form
+// values. Outside JSON, binary values are base64 encoded in 'secret_binary',
+// tags are repeated 'tag=key=value' fields and stages repeated 'version_stage'
+// fields.
+func parseSecretRequest(r *http.Request) (SecretRequest, error) {
+	var req SecretRequest
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "application/json":
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, errors.New("Invalid JSON body")
+		}
+		// An empty "secret_binary" decodes to an empty, non-nil slice that
+		// AWS would reject.
+		if len(req.SecretBinary) == 0 {
+			req.SecretBinary = nil
+		}
+		return req, nil
+	case "application/octet-stream":
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSecretSize+1))
+		if err != nil {
+			return req, errors.New("Error reading request body")
+		}
+		if len(body) > maxSecretSize {
+			return req, errors.New("Secret value exceeds 65536 bytes")
+		}
+		if len(body) == 0 {
+			return req, errors.New("Request body is empty")
+		}
+		req.SecretBinary = body
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return req, errors.New("Invalid form data")
+	}
+	req.Name = r.FormValue("name")
+	req.SecretString = r.FormValue("secret_string")
+	req.KmsKeyID = r.FormValue("kms_key_id")
+	req.Description = r.FormValue("description")
+	req.VersionStages = r.Form["version_stage"]
+	if b := r.FormValue("secret_binary"); b != "" {
+		decoded, err := base64.StdEncoding.DecodeString(b)
+		if err != nil {
+			return req, errors.New("Parameter 'secret_binary' must be base64 encoded")
+		}
+		req.SecretBinary = decoded
+	}
+	for _, tag := range r.Form["tag"] {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			return req, errors.New("Parameter 'tag' must be of the form key=value")
+		}
+		if req.Tags == nil {
+			req.Tags = map[string]string{}
+		}
+		req.Tags[key] = value
+	}
+	return req, nil
+}
+
+// handlePostSecret creates a new secret.
+func handlePostSecret(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	req, err := parseSecretRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+	if req.SecretString != "" && len(req.SecretBinary) > 0 {
+		http.Error(w, "Parameters 'secret_string' and 'secret_binary' are mutually exclusive", http.StatusBadRequest)
+		return
+	}
+	if !req.hasValue() {
+		http.Error(w, "Parameter 'secret_string' or 'secret_binary' is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.VersionStages) > 0 {
+		http.Error(w, "Version stages can only be set when updating a secret", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	out, err := CreateSecret(ctx, svc, req)
+	if err != nil {
+		var exists *types.ResourceExistsException
+		if errors.As(err, &exists) {
+			http.Error(w, "Secret already exists, use PUT to update it", http.StatusConflict)
+			return
+		}
+		slog.Error("failed to create secret", "name", req.Name, "error", err)
+		http.Error(w, "Error creating secret", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("secret created", "name", req.Name)
+	writeJSON(w, secretResponse{
+		ARN:       aws.ToString(out.ARN),
+		Name:      aws.ToString(out.Name),
+		VersionID: aws.ToString(out.VersionId),
+	})
+}
+
+// handlePutSecret updates an existing secret. A new description or KMS key
+// goes through UpdateSecret, a plain value (optionally with custom version
+// stages) through PutSecretValue.
+func handlePutSecret(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	req, err := parseSecretRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+	if req.SecretString != "" && len(req.SecretBinary) > 0 {
+		http.Error(w, "Parameters 'secret_string' and 'secret_binary' are mutually exclusive", http.StatusBadRequest)
+		return
+	}
+	if len(req.Tags) > 0 {
+		http.Error(w, "Tags can only be set when creating a secret", http.StatusBadRequest)
+		return
+	}
+	if req.hasMetadata() && len(req.VersionStages) > 0 {
+		http.Error(w, "Version stages cannot be combined with 'description' or 'kms_key_id'", http.StatusBadRequest)
+		return
+	}
+	if !req.hasMetadata() && !req.hasValue() {
+		http.Error(w, "Nothing to update", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	var result secretResponse
+	if req.hasMetadata() {
+		var out *secretsmanager.UpdateSecretOutput
+		if out, err = UpdateSecret(ctx, svc, req); err == nil {
+			result = secretResponse{ARN: aws.ToString(out.ARN), Name: aws.ToString(out.Name), VersionID: aws.ToString(out.VersionId)}
+		}
+	} else {
+		var out *secretsmanager.PutSecretValueOutput
+		if out, err = PutSecretValue(ctx, svc, req); err == nil {
+			result = secretResponse{
+				ARN:           aws.ToString(out.ARN),
+				Name:          aws.ToString(out.Name),
+				VersionID:     aws.ToString(out.VersionId),
+				VersionStages: out.VersionStages,
+			}
+		}
+	}
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			http.Error(w, "Secret not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to update secret", "name", req.Name, "error", err)
+		http.Error(w, "Error updating secret", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("secret updated", "name", req.Name, "version", result.VersionID)
+	writeJSON(w, result)
+}
+
+func handleDeleteSecret(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	query := r.URL.Query()
+	name := query.Get("name")
+	if name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+
+	force := false
+	if v := query.Get("force"); v != "" {
+		var err error
+		if force, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Parameter 'force' must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var window int64
+	if v := query.Get("recovery_window_days"); v != "" {
+		if force {
+			http.Error(w, "Parameters 'force' and 'recovery_window_days' are mutually exclusive", http.StatusBadRequest)
+			return
+		}
+		var err error
+		window, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || window < 7 || window > 30 {
+			http.Error(w, "Parameter 'recovery_window_days' must be between 7 and 30", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	out, err := DeleteSecret(ctx, svc, name, window, force)
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			http.Error(w, "Secret not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to delete secret", "name", name, "error", err)
+		http.Error(w, "Error deleting secret", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("secret deleted", "name", name, "force", force)
+	writeJSON(w, map[string]any{
+		"arn":           aws.ToString(out.ARN),
+		"name":          aws.ToString(out.Name),
+		"deletion_date": out.DeletionDate,
+	})
+}
+
+// HandleSecretRestore cancels the scheduled deletion of the secret given by
+// the 'name' query or form parameter.
+func HandleSecretRestore(w http.ResponseWriter, r *http.Request, svc SecretsManagerAPI) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	out, err := RestoreSecret(ctx, svc, name)
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			http.Error(w, "Secret not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to restore secret", "name", name, "error", err)
+		http.Error(w, "Error restoring secret", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("secret restored", "name", name)
+	writeJSON(w, secretResponse{ARN: aws.ToString(out.ARN), Name: aws.ToString(out.Name)})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to encode response", "error", err)
+	}
+}
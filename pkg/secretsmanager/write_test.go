@@ -0,0 +1,202 @@
+package secretsmanager
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+func TestHandlePostSecret_Form(t *testing.T) {
+	mock := &mockSM{}
+	form := url.Values{"name": {"app/db"}, "secret_string": {"pw"}, "kms_key_id": {"alias/app"}, "tag": {"team=core"}}
+
+	req := httptest.NewRequest("POST", "/secrets", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	in := mock.createInput
+	if aws.ToString(in.SecretString) != "pw" || aws.ToString(in.KmsKeyId) != "alias/app" || len(in.Tags) != 1 {
+		t.Errorf("got input %+v", in)
+	}
+}
+
+func TestHandlePostSecret_JSONBinary(t *testing.T) {
+	mock := &mockSM{}
+	body := `{"name":"app/cert","secret_binary":"AQID","description":"cert"}`
+
+	req := httptest.NewRequest("POST", "/secrets", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if !bytes.Equal(mock.createInput.SecretBinary, []byte{1, 2, 3}) || mock.createInput.SecretString != nil {
+		t.Errorf("got input %+v", mock.createInput)
+	}
+}
+
+func TestHandlePostSecret_Exists(t *testing.T) {
+	req := httptest.NewRequest("POST", "/secrets?name=app/db&secret_string=pw", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, &mockSM{err: &types.ResourceExistsException{}})
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("got %d want %d", rr.Code, http.StatusConflict)
+	}
+}
+
+func TestHandlePutSecret_RawBinaryWithStages(t *testing.T) {
+	mock := &mockSM{}
+
+	req := httptest.NewRequest("PUT", "/secrets?name=app/key&version_stage=AWSPENDING", bytes.NewReader([]byte{0xff, 0x00}))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	in := mock.putInput
+	if !bytes.Equal(in.SecretBinary, []byte{0xff, 0x00}) || len(in.VersionStages) != 1 || in.VersionStages[0] != "AWSPENDING" {
+		t.Errorf("got input %+v", in)
+	}
+	if mock.updateInput != nil {
+		t.Errorf("UpdateSecret must not be called for a plain value")
+	}
+}
+
+func TestHandlePostSecret_EmptyBinary(t *testing.T) {
+	for contentType, body := range map[string]string{
+		"application/octet-stream": "",
+		"application/json":         `{"name":"app/key","secret_binary":""}`,
+	} {
+		mock := &mockSM{}
+
+		req := httptest.NewRequest("POST", "/secrets?name=app/key", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rr := httptest.NewRecorder()
+		HandleSecrets(rr, req, mock)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", contentType, rr.Code, http.StatusBadRequest)
+		}
+		if mock.createInput != nil {
+			t.Errorf("%s: CreateSecret must not be called", contentType)
+		}
+	}
+}
+
+func TestHandlePutSecret_Metadata(t *testing.T) {
+	mock := &mockSM{}
+
+	req := httptest.NewRequest("PUT", "/secrets?name=app/db&description=rotated", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if aws.ToString(mock.updateInput.Description) != "rotated" || mock.putInput != nil {
+		t.Errorf("got update %+v put %+v", mock.updateInput, mock.putInput)
+	}
+}
+
+func TestHandlePutSecret_InvalidCombinations(t *testing.T) {
+	for _, query := range []string{
+		"name=app/db",
+		"name=app/db&secret_string=pw&tag=a=b",
+		"name=app/db&description=d&version_stage=AWSPENDING",
+		"secret_string=pw",
+		"name=app/db&secret_string=pw&secret_binary=AQID",
+	} {
+		req := httptest.NewRequest("PUT", "/secrets?"+query, nil)
+		rr := httptest.NewRecorder()
+		HandleSecrets(rr, req, &mockSM{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandlePutSecret_NotFound(t *testing.T) {
+	req := httptest.NewRequest("PUT", "/secrets?name=app/db&secret_string=pw", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, &mockSM{err: &types.ResourceNotFoundException{}})
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleDeleteSecret(t *testing.T) {
+	mock := &mockSM{}
+	req := httptest.NewRequest("DELETE", "/secrets?name=review/x&recovery_window_days=7", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if aws.ToInt64(mock.deleteInput.RecoveryWindowInDays) != 7 || mock.deleteInput.ForceDeleteWithoutRecovery != nil {
+		t.Errorf("got input %+v", mock.deleteInput)
+	}
+}
+
+func TestHandleDeleteSecret_Force(t *testing.T) {
+	mock := &mockSM{}
+	req := httptest.NewRequest("DELETE", "/secrets?name=review/x&force=true", nil)
+	rr := httptest.NewRecorder()
+	HandleSecrets(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if !aws.ToBool(mock.deleteInput.ForceDeleteWithoutRecovery) || mock.deleteInput.RecoveryWindowInDays != nil {
+		t.Errorf("got input %+v", mock.deleteInput)
+	}
+}
+
+func TestHandleDeleteSecret_InvalidOptions(t *testing.T) {
+	for _, query := range []string{"", "name=x&recovery_window_days=3", "name=x&force=true&recovery_window_days=7", "name=x&force=maybe"} {
+		req := httptest.NewRequest("DELETE", "/secrets?"+query, nil)
+		rr := httptest.NewRecorder()
+		HandleSecrets(rr, req, &mockSM{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandleSecretRestore(t *testing.T) {
+	req := httptest.NewRequest("POST", "/secrets/restore?name=review/x", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretRestore(rr, req, &mockSM{})
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("got %d want %d", rr.Code, http.StatusOK)
+	}
+}
+
+func TestHandleSecretRestore_NotFound(t *testing.T) {
+	req := httptest.NewRequest("POST", "/secrets/restore?name=review/x", nil)
+	rr := httptest.NewRecorder()
+	HandleSecretRestore(rr, req, &mockSM{err: &types.ResourceNotFoundException{}})
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
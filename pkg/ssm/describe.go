@@ -0,0 +1,189 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// DescribeParameters returns the metadata of every parameter matching
+// filters, following all result pages. Values are never part of the result.
+func DescribeParameters(ctx context.Context, svc SSMAPI, filters []types.ParameterStringFilter) ([]types.ParameterMetadata, error) {
+	paginator := ssm.NewDescribeParametersPaginator(svc, &ssm.DescribeParametersInput{
+		ParameterFilters: filters,
+	})
+
+	var params []types.ParameterMetadata
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, page.Parameters...)
+	}
+	return params, nil
+}
+
+type policyEntry struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Text   string `json:"text"`
+}
+
+type metadataEntry struct {
+	Name             string        `json:"name"`
+	ARN              string        `json:"arn,omitempty"`
+	Type             string        `json:"type"`
+	Tier             string        `json:"tier,omitempty"`
+	Version          int64         `json:"version"`
+	Description      string        `json:"description,omitempty"`
+	KeyID            string        `json:"key_id,omitempty"`
+	DataType         string        `json:"data_type,omitempty"`
+	AllowedPattern   string        `json:"allowed_pattern,omitempty"`
+	LastModifiedUser string        `json:"last_modified_user,omitempty"`
+	LastModifiedDate *time.Time    `json:"last_modified_date,omitempty"`
+	Policies         []policyEntry `json:"policies,omitempty"`
+}
+
+// describeFilters translates the query parameters of HandleSSMDescribe into
+// DescribeParameters filters. Tags are given as 'tag=key' to match any value
+// or 'tag=key=value' to match a specific one.
+func describeFilters(r *http.Request) ([]types.ParameterStringFilter, error) {
+	query := r.URL.Query()
+	var filters []types.ParameterStringFilter
+
+	if prefix := query.Get("begins_with"); prefix != "" {
+		filters = append(filters, types.ParameterStringFilter{
+			Key:    aws.String("Name"),
+			Option: aws.String("BeginsWith"),
+			Values: []string{prefix},
+		})
+	}
+	if path := query.Get("path"); path != "" {
+		recursive, err := boolParam(query.Get("recursive"))
+		if err != nil {
+			return nil, errors.New("Parameter 'recursive' must be a boolean")
+		}
+		option := "OneLevel"
+		if recursive {
+			option = "Recursive"
+		}
+		filters = append(filters, types.ParameterStringFilter{
+			Key:    aws.String("Path"),
+			Option: aws.String(option),
+			Values: []string{path},
+		})
+	}
+	if paramType := query.Get("type"); paramType != "" {
+		if !slices.Contains(types.ParameterTypeString.Values(), types.ParameterType(paramType)) {
+			return nil, errors.New("Parameter 'type' must be 'String', 'StringList' or 'SecureString'")
+		}
+		filters = append(filters, types.ParameterStringFilter{
+			Key:    aws.String("Type"),
+			Option: aws.String("Equals"),
+			Values: []string{paramType},
+		})
+	}
+	for _, tag := range query["tag"] {
+		key, value, hasValue := strings.Cut(tag, "=")
+		if key == "" {
+			return nil, errors.New("Parameter 'tag' must be of the form key or key=value")
+		}
+		filter := types.ParameterStringFilter{Key: aws.String("tag:" + key)}
+		if hasValue {
+			filter.Option = aws.String("Equals")
+			filter.Values = []string{value}
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// HandleSSMDescribe searches parameter metadata. Supported filters are
+// 'begins_with', 'path' (with 'recursive'), 'type', repeated 'tag' and
+// 'label'. DescribeParameters cannot filter by label, so 'label' requires
+// 'path' and is resolved through GetParametersByPath. That call reads values
+// (not decrypted) into the server, so with 'label' the endpoint is no longer
+// metadata-only and needs value-read permission; the values are discarded
+// and never returned. Without 'label' no values are read.
+func HandleSSMDescribe(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filters, err := describeFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	label := query.Get("label")
+	if label != "" && query.Get("path") == "" {
+		http.Error(w, "Parameter 'label' requires 'path'", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	params, err := DescribeParameters(ctx, svc, filters)
+	if err != nil {
+		slog.Error("failed to describe parameters", "error", err)
+		http.Error(w, "Error describing parameters", http.StatusInternalServerError)
+		return
+	}
+
+	if label != "" {
+		recursive, _ := boolParam(query.Get("recursive"))
+		labelled, err := parameterNamesByPath(ctx, svc, query.Get("path"), recursive, []types.ParameterStringFilter{{
+			Key:    aws.String("Label"),
+			Option: aws.String("Equals"),
+			Values: []string{label},
+		}})
+		if err != nil {
+			slog.Error("failed to list labelled parameters", "label", label, "error", err)
+			http.Error(w, "Error describing parameters", http.StatusInternalServerError)
+			return
+		}
+		params = slices.DeleteFunc(params, func(p types.ParameterMetadata) bool {
+			return !slices.Contains(labelled, aws.ToString(p.Name))
+		})
+	}
+
+	entries := make([]metadataEntry, 0, len(params))
+	for _, p := range params {
+		entry := metadataEntry{
+			Name:             aws.ToString(p.Name),
+			ARN:              aws.ToString(p.ARN),
+			Type:             string(p.Type),
+			Tier:             string(p.Tier),
+			Version:          p.Version,
+			Description:      aws.ToString(p.Description),
+			KeyID:            aws.ToString(p.KeyId),
+			DataType:         aws.ToString(p.DataType),
+			AllowedPattern:   aws.ToString(p.AllowedPattern),
+			LastModifiedUser: aws.ToString(p.LastModifiedUser),
+			LastModifiedDate: p.LastModifiedDate,
+		}
+		for _, policy := range p.Policies {
+			entry.Policies = append(entry.Policies, policyEntry{
+				Type:   aws.ToString(policy.PolicyType),
+				Status: aws.ToString(policy.PolicyStatus),
+				Text:   aws.ToString(policy.PolicyText),
+			})
+		}
+		entries = append(entries, entry)
+	}
+
+	writeJSON(w, entries)
+}
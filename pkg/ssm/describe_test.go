@@ -0,0 +1,85 @@
+package ssm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestHandleSSMDescribe(t *testing.T) {
+	mock := &mockSSM{describe: ssm.DescribeParametersOutput{
+		Parameters: []types.ParameterMetadata{
+			{Name: aws.String("/app/a"), Type: types.ParameterTypeSecureString, Version: 2},
+		},
+	}}
+
+	req := httptest.NewRequest("GET", "/ssm/describe?path=/app/&recursive=true&type=SecureString&tag=team=core&tag=owner", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMDescribe(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if len(mock.filters) != 4 {
+		t.Fatalf("got %d filters want 4", len(mock.filters))
+	}
+	if aws.ToString(mock.filters[0].Option) != "Recursive" {
+		t.Errorf("got path option %q want Recursive", aws.ToString(mock.filters[0].Option))
+	}
+	if aws.ToString(mock.filters[2].Key) != "tag:team" || mock.filters[2].Values[0] != "core" {
+		t.Errorf("got tag filter %+v", mock.filters[2])
+	}
+	if mock.filters[3].Values != nil {
+		t.Errorf("got tag key filter values %v want none", mock.filters[3].Values)
+	}
+
+	var got []metadataEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "/app/a" || got[0].Version != 2 {
+		t.Errorf("got %+v", got)
+	}
+	if strings.Contains(rr.Body.String(), "value") {
+		t.Errorf("response must not contain values: %s", rr.Body.String())
+	}
+}
+
+func TestHandleSSMDescribe_Label(t *testing.T) {
+	mock := &mockSSM{
+		describe: ssm.DescribeParametersOutput{Parameters: []types.ParameterMetadata{
+			{Name: aws.String("/app/a")}, {Name: aws.String("/app/b")},
+		}},
+		pathPages: map[string]ssm.GetParametersByPathOutput{
+			"": {Parameters: []types.Parameter{{Name: aws.String("/app/b")}}},
+		},
+	}
+
+	req := httptest.NewRequest("GET", "/ssm/describe?path=/app/&label=prod", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMDescribe(rr, req, mock)
+
+	var got []metadataEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "/app/b" {
+		t.Errorf("got %+v want only /app/b", got)
+	}
+}
+
+func TestHandleSSMDescribe_LabelWithoutPath(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/describe?label=prod", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMDescribe(rr, req, &mockSSM{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
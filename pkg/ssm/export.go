@@ -0,0 +1,178 @@
+package ssm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// envKeyPattern matches names that are valid shell variable identifiers.
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// exportOptions controls how parameter names are turned into keys.
+type exportOptions struct {
+	stripPrefix bool
+	uppercase   bool
+	sanitize    bool
+}
+
+// exportKey derives the exported key for a parameter name below path.
+func exportKey(name, path string, opts exportOptions) string {
+	key := name
+	if opts.stripPrefix {
+		key = trimPathPrefix(key, path)
+	}
+	if opts.sanitize {
+		key = strings.NewReplacer("/", "_", "-", "_").Replace(strings.TrimPrefix(key, "/"))
+	}
+	if opts.uppercase {
+		key = strings.ToUpper(key)
+	}
+	return key
+}
+
+// HandleSSMExport renders a parameter hierarchy as a dotenv file, a shell
+// script of export statements, a JSON object or a YAML mapping.
+func HandleSSMExport(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	path := query.Get("path")
+	if path == "" {
+		http.Error(w, "Parameter 'path' is required", http.StatusBadRequest)
+		return
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		format = "dotenv"
+	}
+	if format != "dotenv" && format != "shell" && format != "json" && format != "yaml" {
+		http.Error(w, "Parameter 'format' must be 'dotenv', 'shell', 'json' or 'yaml'", http.StatusBadRequest)
+		return
+	}
+
+	recursive, err := boolParam(query.Get("recursive"))
+	if err != nil {
+		http.Error(w, "Parameter 'recursive' must be a boolean", http.StatusBadRequest)
+		return
+	}
+	var opts exportOptions
+	if opts.stripPrefix, err = boolParamDefault(query.Get("strip_prefix"), true); err != nil {
+		http.Error(w, "Parameter 'strip_prefix' must be a boolean", http.StatusBadRequest)
+		return
+	}
+	if opts.uppercase, err = boolParam(query.Get("uppercase")); err != nil {
+		http.Error(w, "Parameter 'uppercase' must be a boolean", http.StatusBadRequest)
+		return
+	}
+	if opts.sanitize, err = boolParamDefault(query.Get("sanitize"), true); err != nil {
+		http.Error(w, "Parameter 'sanitize' must be a boolean", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	params, err := GetParametersByPath(ctx, svc, path, recursive)
+	if err != nil {
+		slog.Error("failed to fetch parameters by path", "path", path, "error", err)
+		http.Error(w, "Error fetching parameters", http.StatusInternalServerError)
+		return
+	}
+
+	values := make(map[string]string, len(params))
+	for _, p := range params {
+		name := aws.ToString(p.Name)
+		key := exportKey(name, path, opts)
+		if _, dup := values[key]; dup {
+			http.Error(w, fmt.Sprintf("Several parameters map to key '%s'", key), http.StatusBadRequest)
+			return
+		}
+		if (format == "dotenv" || format == "shell") && !envKeyPattern.MatchString(key) {
+			http.Error(w, fmt.Sprintf("Parameter '%s' maps to invalid variable name '%s'", name, key), http.StatusBadRequest)
+			return
+		}
+		values[key] = aws.ToString(p.Value)
+	}
+
+	if format == "json" {
+		writeJSON(w, values)
+		return
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var buf bytes.Buffer
+	for _, k := range keys {
+		switch format {
+		case "dotenv":
+			fmt.Fprintf(&buf, "%s=%s\n", k, dotenvQuote(values[k]))
+		case "shell":
+			fmt.Fprintf(&buf, "export %s=%s\n", k, shellQuote(values[k]))
+		case "yaml":
+			fmt.Fprintf(&buf, "%s: %s\n", yamlQuote(k), yamlQuote(values[k]))
+		}
+	}
+
+	if format == "yaml" {
+		w.Header().Set("Content-Type", "application/yaml")
+	} else {
+		w.Header().Set("Content-Type", "text/plain")
+	}
+	w.Write(buf.Bytes())
+}
+
+// boolParamDefault parses an optional boolean query value, falling back to def
+// when it is empty.
+func boolParamDefault(value string, def bool) (bool, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// dotenvQuote wraps s in double quotes, escaping the characters dotenv
+// parsers interpret inside them.
+func dotenvQuote(s string) string {
+	return `"` + strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"$", `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+	).Replace(s) + `"`
+}
+
+// shellQuote wraps s in single quotes, which POSIX shells take literally,
+// closing and reopening the quotes around embedded single quotes.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// yamlQuote renders s as a double-quoted scalar. JSON string escapes are a
+// subset of YAML's, so the JSON encoding is valid YAML.
+func yamlQuote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
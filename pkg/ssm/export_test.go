@@ -0,0 +1,76 @@
+package ssm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func exportMock() *mockSSM {
+	return &mockSSM{pathPages: map[string]ssm.GetParametersByPathOutput{
+		"": {Parameters: []types.Parameter{
+			{Name: aws.String("/app/db-user"), Value: aws.String("admin")},
+			{Name: aws.String("/app/db/pass"), Value: aws.String("it's \"$ecret\"\nline2")},
+		}},
+	}}
+}
+
+func TestHandleSSMExport_Dotenv(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/export?path=/app/&recursive=true&uppercase=true", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMExport(rr, req, exportMock())
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	want := "DB_PASS=\"it's \\\"\\$ecret\\\"\\nline2\"\nDB_USER=\"admin\"\n"
+	if rr.Body.String() != want {
+		t.Errorf("got %q want %q", rr.Body.String(), want)
+	}
+}
+
+func TestHandleSSMExport_Shell(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/export?path=/app/&recursive=true&format=shell", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMExport(rr, req, exportMock())
+
+	want := "export db_pass='it'\\''s \"$ecret\"\nline2'\nexport db_user='admin'\n"
+	if rr.Body.String() != want {
+		t.Errorf("got %q want %q", rr.Body.String(), want)
+	}
+}
+
+func TestHandleSSMExport_YAML(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/export?path=/app/&format=yaml&sanitize=false", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMExport(rr, req, exportMock())
+
+	want := "\"db-user\": \"admin\"\n\"db/pass\": \"it's \\\"$ecret\\\"\\nline2\"\n"
+	if rr.Body.String() != want {
+		t.Errorf("got %q want %q", rr.Body.String(), want)
+	}
+}
+
+func TestHandleSSMExport_InvalidKey(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/export?path=/app/&sanitize=false", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMExport(rr, req, exportMock())
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleSSMExport_InvalidFormat(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/export?path=/app/&format=xml", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMExport(rr, req, exportMock())
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
@@ -2,8 +2,13 @@ package ssm
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -15,8 +20,20 @@ import (
 type SSMAPI interface {
 	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
 	PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error)
+	GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
+	GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
+	GetParameterHistory(ctx context.Context, params *ssm.GetParameterHistoryInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error)
+	DeleteParameter(ctx context.Context, params *ssm.DeleteParameterInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParameterOutput, error)
+	DeleteParameters(ctx context.Context, params *ssm.DeleteParametersInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParametersOutput, error)
+	LabelParameterVersion(ctx context.Context, params *ssm.LabelParameterVersionInput, optFns ...func(*ssm.Options)) (*ssm.LabelParameterVersionOutput, error)
+	UnlabelParameterVersion(ctx context.Context, params *ssm.UnlabelParameterVersionInput, optFns ...func(*ssm.Options)) (*ssm.UnlabelParameterVersionOutput, error)
+	DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
 }
 
+// maxGetParameters is the number of names GetParameters and DeleteParameters
+// accept per call.
+const maxGetParameters = 10
+
 func GetParameter(ctx context.Context, svc SSMAPI, name string) (*ssm.GetParameterOutput, error) {
 	return svc.GetParameter(ctx, &ssm.GetParameterInput{
 		Name:           aws.String(name),
@@ -24,11 +41,243 @@ func GetParameter(ctx context.Context, svc SSMAPI, name string) (*ssm.GetParamet
 	})
 }
 
-func PutParameter(ctx context.Context, svc SSMAPI, name, value string, paramType types.ParameterType) (*ssm.PutParameterOutput, error) {
-	return svc.PutParameter(ctx, &ssm.PutParameterInput{
-		Name:  aws.String(name),
-		Value: aws.String(value),
-		Type:  paramType,
+// GetParametersByPath returns every decrypted parameter below path, following
+// all result pages.
+func GetParametersByPath(ctx context.Context, svc SSMAPI, path string, recursive bool) ([]types.Parameter, error) {
+	paginator := ssm.NewGetParametersByPathPaginator(svc, &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(recursive),
+		WithDecryption: aws.Bool(true),
+	})
+
+	var params []types.Parameter
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, page.Parameters...)
+	}
+	return params, nil
+}
+
+// GetParameters resolves names in chunks of maxGetParameters and returns the
+// decrypted parameters together with the names SSM reported as invalid.
+func GetParameters(ctx context.Context, svc SSMAPI, names []string) ([]types.Parameter, []string, error) {
+	var params []types.Parameter
+	invalid := []string{}
+	for start := 0; start < len(names); start += maxGetParameters {
+		end := min(start+maxGetParameters, len(names))
+		out, err := svc.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          names[start:end],
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		params = append(params, out.Parameters...)
+		invalid = append(invalid, out.InvalidParameters...)
+	}
+	return params, invalid, nil
+}
+
+// parameterNamesByPath lists the names below path that match filters, without
+// decrypting values.
+func parameterNamesByPath(ctx context.Context, svc SSMAPI, path string, recursive bool, filters []types.ParameterStringFilter) ([]string, error) {
+	paginator := ssm.NewGetParametersByPathPaginator(svc, &ssm.GetParametersByPathInput{
+		Path:             aws.String(path),
+		Recursive:        aws.Bool(recursive),
+		ParameterFilters: filters,
+	})
+
+	var names []string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page.Parameters {
+			names = append(names, aws.ToString(p.Name))
+		}
+	}
+	return names, nil
+}
+
+// GetParameterHistory returns every stored version of a parameter, oldest
+// first, following all result pages.
+func GetParameterHistory(ctx context.Context, svc SSMAPI, name string, decrypt bool) ([]types.ParameterHistory, error) {
+	paginator := ssm.NewGetParameterHistoryPaginator(svc, &ssm.GetParameterHistoryInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(decrypt),
+	})
+
+	var history []types.ParameterHistory
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, page.Parameters...)
+	}
+	return history, nil
+}
+
+// PutParameterRequest describes a parameter write. It doubles as the JSON
+// body accepted by HandlePostSSM.
+type PutParameterRequest struct {
+	Name           string              `json:"name"`
+	Value          string              `json:"value"`
+	Type           types.ParameterType `json:"type"`
+	Overwrite      bool                `json:"overwrite"`
+	KeyID          string              `json:"key_id"`
+	Tier           types.ParameterTier `json:"tier"`
+	Description    string              `json:"description"`
+	AllowedPattern string              `json:"allowed_pattern"`
+	DataType       string              `json:"data_type"`
+	Tags           map[string]string   `json:"tags"`
+	Policies       []ParameterPolicy   `json:"policies"`
+}
+
+// ParameterPolicy is an Advanced-tier parameter policy. Expiration uses
+// Timestamp (RFC 3339), ExpirationNotification uses Before and
+// NoChangeNotification uses After, both counted in Unit ("Days" or "Hours",
+// default "Days").
+type ParameterPolicy struct {
+	Type      string `json:"type"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Before    int    `json:"before,omitempty"`
+	After     int    `json:"after,omitempty"`
+	Unit      string `json:"unit,omitempty"`
+}
+
+type policyDocument struct {
+	Type       string            `json:"Type"`
+	Version    string            `json:"Version"`
+	Attributes map[string]string `json:"Attributes"`
+}
+
+// encodePolicies renders policies in the JSON format PutParameter expects.
+func encodePolicies(policies []ParameterPolicy) (string, error) {
+	docs := make([]policyDocument, 0, len(policies))
+	for _, p := range policies {
+		unit := p.Unit
+		if unit == "" {
+			unit = "Days"
+		}
+		if p.Type != "Expiration" && unit != "Days" && unit != "Hours" {
+			return "", errors.New("Policy 'unit' must be 'Days' or 'Hours'")
+		}
+
+		doc := policyDocument{Type: p.Type, Version: "1.0"}
+		switch p.Type {
+		case "Expiration":
+			ts, err := time.Parse(time.RFC3339, p.Timestamp)
+			if err != nil {
+				return "", errors.New("Expiration policy requires an RFC 3339 'timestamp'")
+			}
+			doc.Attributes = map[string]string{"Timestamp": ts.UTC().Format("2006-01-02T15:04:05.000Z")}
+		case "ExpirationNotification":
+			if p.Before < 1 {
+				return "", errors.New("ExpirationNotification policy requires a positive 'before'")
+			}
+			doc.Attributes = map[string]string{"Before": strconv.Itoa(p.Before), "Unit": unit}
+		case "NoChangeNotification":
+			if p.After < 1 {
+				return "", errors.New("NoChangeNotification policy requires a positive 'after'")
+			}
+			doc.Attributes = map[string]string{"After": strconv.Itoa(p.After), "Unit": unit}
+		default:
+			return "", errors.New("Policy 'type' must be 'Expiration', 'ExpirationNotification' or 'NoChangeNotification'")
+		}
+		docs = append(docs, doc)
+	}
+
+	b, err := json.Marshal(docs)
+	return string(b), err
+}
+
+func PutParameter(ctx context.Context, svc SSMAPI, req PutParameterRequest) (*ssm.PutParameterOutput, error) {
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(req.Name),
+		Value:     aws.String(req.Value),
+		Type:      req.Type,
+		Overwrite: aws.Bool(req.Overwrite),
+		Tier:      req.Tier,
+	}
+	if req.KeyID != "" {
+		input.KeyId = aws.String(req.KeyID)
+	}
+	if req.Description != "" {
+		input.Description = aws.String(req.Description)
+	}
+	if req.AllowedPattern != "" {
+		input.AllowedPattern = aws.String(req.AllowedPattern)
+	}
+	if req.DataType != "" {
+		input.DataType = aws.String(req.DataType)
+	}
+	for key, value := range req.Tags {
+		input.Tags = append(input.Tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	if len(req.Policies) > 0 {
+		policies, err := encodePolicies(req.Policies)
+		if err != nil {
+			return nil, err
+		}
+		input.Policies = aws.String(policies)
+		// Policies are only available on the Advanced tier.
+		if input.Tier == "" {
+			input.Tier = types.ParameterTierAdvanced
+		}
+	}
+	return svc.PutParameter(ctx, input)
+}
+
+func DeleteParameter(ctx context.Context, svc SSMAPI, name string) error {
+	_, err := svc.DeleteParameter(ctx, &ssm.DeleteParameterInput{
+		Name: aws.String(name),
+	})
+	return err
+}
+
+// DeleteParameters removes names in chunks of maxGetParameters and returns
+// the deleted names together with the names SSM reported as invalid.
+func DeleteParameters(ctx context.Context, svc SSMAPI, names []string) ([]string, []string, error) {
+	deleted := []string{}
+	invalid := []string{}
+	for start := 0; start < len(names); start += maxGetParameters {
+		end := min(start+maxGetParameters, len(names))
+		out, err := svc.DeleteParameters(ctx, &ssm.DeleteParametersInput{
+			Names: names[start:end],
+		})
+		if err != nil {
+			return deleted, invalid, err
+		}
+		deleted = append(deleted, out.DeletedParameters...)
+		invalid = append(invalid, out.InvalidParameters...)
+	}
+	return deleted, invalid, nil
+}
+
+// LabelParameterVersion attaches labels to a parameter version, moving them
+// away from any other version that carried them. A zero version labels the
+// latest version.
+func LabelParameterVersion(ctx context.Context, svc SSMAPI, name string, version int64, labels []string) (*ssm.LabelParameterVersionOutput, error) {
+	input := &ssm.LabelParameterVersionInput{
+		Name:   aws.String(name),
+		Labels: labels,
+	}
+	if version > 0 {
+		input.ParameterVersion = aws.Int64(version)
+	}
+	return svc.LabelParameterVersion(ctx, input)
+}
+
+func UnlabelParameterVersion(ctx context.Context, svc SSMAPI, name string, version int64, labels []string) (*ssm.UnlabelParameterVersionOutput, error) {
+	return svc.UnlabelParameterVersion(ctx, &ssm.UnlabelParameterVersionInput{
+		Name:             aws.String(name),
+		ParameterVersion: aws.Int64(version),
+		Labels:           labels,
 	})
 }
 
@@ -38,69 +287,553 @@ func HandleSSM(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
 		handleGetSSM(w, r, svc)
 	case http.MethodPost:
 		HandlePostSSM(w, r, svc)
+	case http.MethodDelete:
+		handleDeleteSSM(w, r, svc)
 	default:
 		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
 	}
 }
 
 func handleGetSSM(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
-	name := r.URL.Query().Get("name")
+	query := r.URL.Query()
+	name := query.Get("name")
 	if name == "" {
 		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
 		return
 	}
 
+	name, err := ParameterSelector(name, query.Get("version"), query.Get("label"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
 	results, err := GetParameter(ctx, svc, name)
 	if err != nil {
+		// An unknown label surfaces as one of these, depending on whether
+		// the parameter itself exists.
+		var notFound *types.ParameterNotFound
+		var versionNotFound *types.ParameterVersionNotFound
+		if errors.As(err, &notFound) || errors.As(err, &versionNotFound) {
+			http.Error(w, "Parameter not found", http.StatusNotFound)
+			return
+		}
 		slog.Error("failed to fetch parameter", "error", err)
 		http.Error(w, "Error fetching parameter", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("X-Parameter-Version", strconv.FormatInt(results.Parameter.Version, 10))
 	w.Write([]byte(*results.Parameter.Value))
 }
 
+// ParameterSelector appends a version or label selector to name, producing
+// the "name:3" or "name:prod" form understood by GetParameter.
+func ParameterSelector(name, version, label string) (string, error) {
+	switch {
+	case version != "" && label != "":
+		return "", errors.New("Parameters 'version' and 'label' are mutually exclusive")
+	case version != "":
+		if v, err := strconv.ParseInt(version, 10, 64); err != nil || v < 1 {
+			return "", errors.New("Parameter 'version' must be a positive integer")
+		}
+		return name + ":" + version, nil
+	case label != "":
+		return name + ":" + label, nil
+	}
+	return name, nil
+}
+
+// HandlePostSSM writes a parameter. The request is either form encoded, with
+// tags given as repeated 'tag=key=value' fields, or a JSON PutParameterRequest.
+// Parameter policies can only be given in the JSON form.
 func HandlePostSSM(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
+	var req PutParameterRequest
+	if isJSON(r) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			slog.Error("invalid JSON body", "error", err)
+			return
+		}
+	} else {
+		var err error
+		if req, err = parsePutForm(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			slog.Error("invalid form data", "error", err)
+			return
+		}
+	}
+
+	if req.Name == "" || req.Value == "" || req.Type == "" {
+		http.Error(w, "Parameters 'name', 'value', and 'type' are required", http.StatusBadRequest)
+		return
+	}
+	if err := validatePutRequest(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	out, err := PutParameter(ctx, svc, req)
+	if err != nil {
+		var exists *types.ParameterAlreadyExists
+		if errors.As(err, &exists) {
+			http.Error(w, "Parameter already exists, set 'overwrite' to replace it", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error putting parameter", http.StatusInternalServerError)
+		slog.Error("failed to put parameter", "error", err)
+		return
+	}
+
+	slog.Info("parameter uploaded", "name", req.Name, "version", out.Version)
+	writeJSON(w, map[string]any{"version": out.Version, "tier": out.Tier})
+}
+
+func parsePutForm(r *http.Request) (PutParameterRequest, error) {
 	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
-		slog.Error("invalid form data", "error", err)
+		return PutParameterRequest{}, errors.New("Invalid form data")
+	}
+
+	overwrite, err := boolParam(r.FormValue("overwrite"))
+	if err != nil {
+		return PutParameterRequest{}, errors.New("Parameter 'overwrite' must be a boolean")
+	}
+
+	req := PutParameterRequest{
+		Name:           r.FormValue("name"),
+		Value:          r.FormValue("value"),
+		Type:           types.ParameterType(r.FormValue("type")),
+		Overwrite:      overwrite,
+		KeyID:          r.FormValue("key_id"),
+		Tier:           types.ParameterTier(r.FormValue("tier")),
+		Description:    r.FormValue("description"),
+		AllowedPattern: r.FormValue("allowed_pattern"),
+		DataType:       r.FormValue("data_type"),
+	}
+	for _, tag := range r.Form["tag"] {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			return PutParameterRequest{}, errors.New("Parameter 'tag' must be of the form key=value")
+		}
+		if req.Tags == nil {
+			req.Tags = map[string]string{}
+		}
+		req.Tags[key] = value
+	}
+	return req, nil
+}
+
+func validatePutRequest(req PutParameterRequest) error {
+	switch req.Type {
+	case types.ParameterTypeString, types.ParameterTypeStringList, types.ParameterTypeSecureString:
+	default:
+		return errors.New("Parameter 'type' must be 'String', 'StringList' or 'SecureString'")
+	}
+	switch req.Tier {
+	case "", types.ParameterTierStandard, types.ParameterTierAdvanced, types.ParameterTierIntelligentTiering:
+	default:
+		return errors.New("Parameter 'tier' must be 'Standard', 'Advanced' or 'Intelligent-Tiering'")
+	}
+	if req.KeyID != "" && req.Type != types.ParameterTypeSecureString {
+		return errors.New("Parameter 'key_id' is only valid for type 'SecureString'")
+	}
+	// SSM rejects tags on overwrite; they can only be set on creation.
+	if req.Overwrite && len(req.Tags) > 0 {
+		return errors.New("Tags cannot be combined with 'overwrite'")
+	}
+	if len(req.Policies) > 0 {
+		if req.Tier == types.ParameterTierStandard {
+			return errors.New("Parameter policies require tier 'Advanced' or 'Intelligent-Tiering'")
+		}
+		if _, err := encodePolicies(req.Policies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type deleteResponse struct {
+	DeletedParameters []string `json:"deleted_parameters"`
+	InvalidParameters []string `json:"invalid_parameters"`
+}
+
+// handleDeleteSSM deletes the parameters named by repeated 'name' query
+// parameters, a JSON body of the form {"names": [...]}, or everything below
+// 'path'. A single name is deleted with DeleteParameter and yields 404 when it
+// does not exist; everything else goes through DeleteParameters.
+func handleDeleteSSM(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
+	query := r.URL.Query()
+	names := query["name"]
+	if isJSON(r) {
+		var body batchRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			slog.Error("invalid JSON body", "error", err)
+			return
+		}
+		names = append(names, body.Names...)
+	}
+
+	path := query.Get("path")
+	recursive, err := boolParam(query.Get("recursive"))
+	if err != nil {
+		http.Error(w, "Parameter 'recursive' must be a boolean", http.StatusBadRequest)
+		return
+	}
+	if path == "" && len(uniqueNonEmpty(names)) == 0 {
+		http.Error(w, "Parameter 'name' or 'path' is required", http.StatusBadRequest)
 		return
 	}
 
-	name := r.FormValue("name")
-	value := r.FormValue("value")
-	typeStr := r.FormValue("type")
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
 
-	if name == "" || value == "" || typeStr == "" {
-		http.Error(w, "Parameters 'name', 'value', and 'type' are required", http.StatusBadRequest)
+	if path != "" {
+		pathNames, err := parameterNamesByPath(ctx, svc, path, recursive, nil)
+		if err != nil {
+			slog.Error("failed to list parameters by path", "path", path, "error", err)
+			http.Error(w, "Error listing parameters", http.StatusInternalServerError)
+			return
+		}
+		names = append(names, pathNames...)
+	}
+	names = uniqueNonEmpty(names)
+
+	if path == "" && len(names) == 1 {
+		if err := DeleteParameter(ctx, svc, names[0]); err != nil {
+			var notFound *types.ParameterNotFound
+			if errors.As(err, &notFound) {
+				http.Error(w, "Parameter not found", http.StatusNotFound)
+				return
+			}
+			slog.Error("failed to delete parameter", "name", names[0], "error", err)
+			http.Error(w, "Error deleting parameter", http.StatusInternalServerError)
+			return
+		}
+		slog.Info("parameter deleted", "name", names[0])
+		writeJSON(w, deleteResponse{DeletedParameters: names, InvalidParameters: []string{}})
 		return
 	}
 
-	var paramType types.ParameterType
-	switch typeStr {
-	case "String":
-		paramType = types.ParameterTypeString
-	case "SecureString":
-		paramType = types.ParameterTypeSecureString
+	deleted, invalid, err := DeleteParameters(ctx, svc, names)
+	if err != nil {
+		slog.Error("failed to delete parameters", "deleted", deleted, "error", err)
+		http.Error(w, "Error deleting parameters", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("parameters deleted", "count", len(deleted), "invalid", len(invalid))
+	writeJSON(w, deleteResponse{DeletedParameters: deleted, InvalidParameters: invalid})
+}
+
+// LabelRequest is the JSON body accepted by HandleSSMLabels.
+type LabelRequest struct {
+	Name    string   `json:"name"`
+	Version int64    `json:"version"`
+	Labels  []string `json:"labels"`
+}
+
+// HandleSSMLabels attaches labels to a parameter version on POST and removes
+// them on DELETE. The request is either a JSON LabelRequest or form/query
+// values with labels given as repeated 'label' fields.
+func HandleSSMLabels(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req LabelRequest
+	if isJSON(r) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			slog.Error("invalid JSON body", "error", err)
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Invalid form data", http.StatusBadRequest)
+			slog.Error("invalid form data", "error", err)
+			return
+		}
+		req.Name = r.FormValue("name")
+		req.Labels = r.Form["label"]
+		if v := r.FormValue("version"); v != "" {
+			version, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || version < 1 {
+				http.Error(w, "Parameter 'version' must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			req.Version = version
+		}
+	}
+
+	req.Labels = uniqueNonEmpty(req.Labels)
+	if req.Name == "" || len(req.Labels) == 0 {
+		http.Error(w, "Parameters 'name' and 'label' are required", http.StatusBadRequest)
+		return
+	}
+	if r.Method == http.MethodDelete && req.Version < 1 {
+		http.Error(w, "Parameter 'version' is required to remove labels", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	var result any
+	var err error
+	if r.Method == http.MethodPost {
+		var out *ssm.LabelParameterVersionOutput
+		if out, err = LabelParameterVersion(ctx, svc, req.Name, req.Version, req.Labels); err == nil {
+			slog.Info("parameter labelled", "name", req.Name, "version", out.ParameterVersion, "labels", req.Labels)
+			result = map[string]any{
+				"parameter_version": out.ParameterVersion,
+				"invalid_labels":    nonNil(out.InvalidLabels),
+			}
+		}
+	} else {
+		var out *ssm.UnlabelParameterVersionOutput
+		if out, err = UnlabelParameterVersion(ctx, svc, req.Name, req.Version, req.Labels); err == nil {
+			slog.Info("parameter unlabelled", "name", req.Name, "version", req.Version, "labels", out.RemovedLabels)
+			result = map[string]any{
+				"removed_labels": nonNil(out.RemovedLabels),
+				"invalid_labels": nonNil(out.InvalidLabels),
+			}
+		}
+	}
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		var versionNotFound *types.ParameterVersionNotFound
+		if errors.As(err, &notFound) || errors.As(err, &versionNotFound) {
+			http.Error(w, "Parameter version not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to update parameter labels", "name", req.Name, "error", err)
+		http.Error(w, "Error updating parameter labels", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+// HandleSSMPath serves a whole parameter hierarchy as a JSON object keyed by
+// parameter name.
+func HandleSSMPath(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	path := query.Get("path")
+	if path == "" {
+		http.Error(w, "Parameter 'path' is required", http.StatusBadRequest)
+		return
+	}
+
+	recursive, err := boolParam(query.Get("recursive"))
+	if err != nil {
+		http.Error(w, "Parameter 'recursive' must be a boolean", http.StatusBadRequest)
+		return
+	}
+	stripPrefix, err := boolParam(query.Get("strip_prefix"))
+	if err != nil {
+		http.Error(w, "Parameter 'strip_prefix' must be a boolean", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	params, err := GetParametersByPath(ctx, svc, path, recursive)
+	if err != nil {
+		slog.Error("failed to fetch parameters by path", "path", path, "error", err)
+		http.Error(w, "Error fetching parameters", http.StatusInternalServerError)
+		return
+	}
+
+	result := make(map[string]string, len(params))
+	for _, p := range params {
+		name := aws.ToString(p.Name)
+		if stripPrefix {
+			name = trimPathPrefix(name, path)
+		}
+		result[name] = aws.ToString(p.Value)
+	}
+
+	writeJSON(w, result)
+}
+
+type historyEntry struct {
+	Version          int64      `json:"version"`
+	Value            string     `json:"value"`
+	Type             string     `json:"type"`
+	Labels           []string   `json:"labels"`
+	LastModifiedUser string     `json:"last_modified_user,omitempty"`
+	LastModifiedDate *time.Time `json:"last_modified_date,omitempty"`
+}
+
+// HandleSSMHistory serves every version of a parameter as a JSON array.
+// SecureString values are only decrypted when 'decrypt=true' is given.
+func HandleSSMHistory(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("name")
+	if name == "" {
+		http.Error(w, "Parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+
+	decrypt, err := boolParam(query.Get("decrypt"))
+	if err != nil {
+		http.Error(w, "Parameter 'decrypt' must be a boolean", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	history, err := GetParameterHistory(ctx, svc, name, decrypt)
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			http.Error(w, "Parameter not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to fetch parameter history", "name", name, "error", err)
+		http.Error(w, "Error fetching parameter history", http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]historyEntry, 0, len(history))
+	for _, h := range history {
+		entries = append(entries, historyEntry{
+			Version:          h.Version,
+			Value:            aws.ToString(h.Value),
+			Type:             string(h.Type),
+			Labels:           nonNil(h.Labels),
+			LastModifiedUser: aws.ToString(h.LastModifiedUser),
+			LastModifiedDate: h.LastModifiedDate,
+		})
+	}
+
+	writeJSON(w, entries)
+}
+
+type batchRequest struct {
+	Names []string `json:"names"`
+}
+
+type batchResponse struct {
+	Parameters        map[string]string `json:"parameters"`
+	InvalidParameters []string          `json:"invalid_parameters"`
+}
+
+// HandleSSMBatch resolves several parameters at once. Names are taken from
+// repeated 'name' query parameters on GET, or from a JSON body of the form
+// {"names": [...]} on POST.
+func HandleSSMBatch(w http.ResponseWriter, r *http.Request, svc SSMAPI) {
+	var names []string
+	switch r.Method {
+	case http.MethodGet:
+		names = r.URL.Query()["name"]
+	case http.MethodPost:
+		var body batchRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			slog.Error("invalid JSON body", "error", err)
+			return
+		}
+		names = body.Names
 	default:
-		http.Error(w, "Parameter 'type' must be 'String' or 'SecureString'", http.StatusBadRequest)
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	names = uniqueNonEmpty(names)
+	if len(names) == 0 {
+		http.Error(w, "At least one parameter 'name' is required", http.StatusBadRequest)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	_, err := PutParameter(ctx, svc, name, value, paramType)
+	params, invalid, err := GetParameters(ctx, svc, names)
 	if err != nil {
-		http.Error(w, "Error putting parameter", http.StatusInternalServerError)
-		slog.Error("failed to put parameter", "error", err)
+		slog.Error("failed to fetch parameters", "error", err)
+		http.Error(w, "Error fetching parameters", http.StatusInternalServerError)
 		return
 	}
 
-	slog.Info("parameter uploaded", "name", name)
+	result := batchResponse{
+		Parameters:        make(map[string]string, len(params)),
+		InvalidParameters: invalid,
+	}
+	for _, p := range params {
+		result.Parameters[aws.ToString(p.Name)] = aws.ToString(p.Value)
+	}
+
+	writeJSON(w, result)
+}
+
+// uniqueNonEmpty drops empty and repeated names while keeping their order.
+func uniqueNonEmpty(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var out []string
+	for _, n := range names {
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	return out
+}
+
+// trimPathPrefix removes path, and the separator following it, from name.
+func trimPathPrefix(name, path string) string {
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	return strings.TrimPrefix(name, prefix)
+}
+
+// nonNil turns a nil slice into an empty one so it encodes as [] in JSON.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// isJSON reports whether the request body is declared as JSON.
+func isJSON(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
+// boolParam parses an optional boolean query value; an empty value is false.
+func boolParam(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to encode response", "error", err)
+	}
 }
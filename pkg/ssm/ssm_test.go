@@ -2,10 +2,12 @@ package ssm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -15,19 +17,100 @@ import (
 )
 
 type mockSSM struct {
-	getResp ssm.GetParameterOutput
-	putResp ssm.PutParameterOutput
-	err     error
+	getResp   ssm.GetParameterOutput
+	putResp   ssm.PutParameterOutput
+	pathPages map[string]ssm.GetParametersByPathOutput
+	batchCall [][]string
+	getName   string
+	history   []types.ParameterHistory
+	putInput  *ssm.PutParameterInput
+	deleted   []string
+	labelCall *ssm.LabelParameterVersionInput
+	describe  ssm.DescribeParametersOutput
+	filters   []types.ParameterStringFilter
+	err       error
 }
 
 func (m *mockSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	m.getName = aws.ToString(params.Name)
 	return &m.getResp, m.err
 }
 
 func (m *mockSSM) PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error) {
+	m.putInput = params
 	return &m.putResp, m.err
 }
 
+func (m *mockSSM) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	page := m.pathPages[aws.ToString(params.NextToken)]
+	return &page, m.err
+}
+
+// GetParameters echoes every requested name back as a parameter, except names
+// starting with "missing" which are reported as invalid.
+func (m *mockSSM) GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	m.batchCall = append(m.batchCall, params.Names)
+	out := &ssm.GetParametersOutput{}
+	for _, n := range params.Names {
+		if strings.HasPrefix(n, "missing") {
+			out.InvalidParameters = append(out.InvalidParameters, n)
+			continue
+		}
+		out.Parameters = append(out.Parameters, types.Parameter{Name: aws.String(n), Value: aws.String("v-" + n)})
+	}
+	return out, m.err
+}
+
+// GetParameterHistory returns one history entry per page.
+func (m *mockSSM) GetParameterHistory(ctx context.Context, params *ssm.GetParameterHistoryInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	idx := 0
+	if params.NextToken != nil {
+		idx, _ = strconv.Atoi(*params.NextToken)
+	}
+	out := &ssm.GetParameterHistoryOutput{Parameters: m.history[idx : idx+1]}
+	if idx+1 < len(m.history) {
+		out.NextToken = aws.String(strconv.Itoa(idx + 1))
+	}
+	return out, nil
+}
+
+func (m *mockSSM) DeleteParameter(ctx context.Context, params *ssm.DeleteParameterInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParameterOutput, error) {
+	m.deleted = append(m.deleted, aws.ToString(params.Name))
+	return &ssm.DeleteParameterOutput{}, m.err
+}
+
+// DeleteParameters deletes every requested name, except names starting with
+// "missing" which are reported as invalid.
+func (m *mockSSM) DeleteParameters(ctx context.Context, params *ssm.DeleteParametersInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParametersOutput, error) {
+	out := &ssm.DeleteParametersOutput{}
+	for _, n := range params.Names {
+		if strings.HasPrefix(n, "missing") {
+			out.InvalidParameters = append(out.InvalidParameters, n)
+			continue
+		}
+		m.deleted = append(m.deleted, n)
+		out.DeletedParameters = append(out.DeletedParameters, n)
+	}
+	return out, m.err
+}
+
+func (m *mockSSM) LabelParameterVersion(ctx context.Context, params *ssm.LabelParameterVersionInput, optFns ...func(*ssm.Options)) (*ssm.LabelParameterVersionOutput, error) {
+	m.labelCall = params
+	return &ssm.LabelParameterVersionOutput{ParameterVersion: aws.ToInt64(params.ParameterVersion)}, m.err
+}
+
+func (m *mockSSM) UnlabelParameterVersion(ctx context.Context, params *ssm.UnlabelParameterVersionInput, optFns ...func(*ssm.Options)) (*ssm.UnlabelParameterVersionOutput, error) {
+	return &ssm.UnlabelParameterVersionOutput{RemovedLabels: params.Labels}, m.err
+}
+
+func (m *mockSSM) DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error) {
+	m.filters = params.ParameterFilters
+	return &m.describe, m.err
+}
+
 func TestHandleGetSSM(t *testing.T) {
 	mock := &mockSSM{getResp: ssm.GetParameterOutput{
 		Parameter: &types.Parameter{Value: aws.String("test_value")},
@@ -67,6 +150,64 @@ func TestHandleGetSSM_AWSError(t *testing.T) {
 	}
 }
 
+func TestHandleGetSSM_Label(t *testing.T) {
+	mock := &mockSSM{getResp: ssm.GetParameterOutput{
+		Parameter: &types.Parameter{Value: aws.String("v3"), Version: 3},
+	}}
+
+	req := httptest.NewRequest("GET", "/ssm?name=/app/key&label=prod", nil)
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if mock.getName != "/app/key:prod" {
+		t.Errorf("got name %q want /app/key:prod", mock.getName)
+	}
+	if got := rr.Header().Get("X-Parameter-Version"); got != "3" {
+		t.Errorf("got X-Parameter-Version %q want 3", got)
+	}
+}
+
+func TestHandleGetSSM_InvalidSelector(t *testing.T) {
+	for _, query := range []string{"version=abc", "version=0", "version=1&label=prod"} {
+		req := httptest.NewRequest("GET", "/ssm?name=p&"+query, nil)
+		rr := httptest.NewRecorder()
+		HandleSSM(rr, req, &mockSSM{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandleGetSSM_NotFound(t *testing.T) {
+	mock := &mockSSM{err: &types.ParameterNotFound{}}
+
+	req := httptest.NewRequest("GET", "/ssm?name=test", nil)
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, mock)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleGetSSM_VersionNotFound(t *testing.T) {
+	for _, query := range []string{"version=99", "label=missing"} {
+		mock := &mockSSM{err: &types.ParameterVersionNotFound{}}
+
+		req := httptest.NewRequest("GET", "/ssm?name=test&"+query, nil)
+		rr := httptest.NewRecorder()
+		HandleSSM(rr, req, mock)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: got %d want %d", query, rr.Code, http.StatusNotFound)
+		}
+	}
+}
+
 func TestHandlePostSSM(t *testing.T) {
 	mock := &mockSSM{}
 	form := url.Values{"name": {"p"}, "value": {"v"}, "type": {"SecureString"}}
@@ -107,12 +248,345 @@ func TestHandlePostSSM_MissingFields(t *testing.T) {
 	}
 }
 
-func TestHandleSSM_MethodNotAllowed(t *testing.T) {
+func TestHandlePostSSM_JSON(t *testing.T) {
+	mock := &mockSSM{}
+	body := `{"name":"/app/hosts","value":"a,b","type":"StringList","overwrite":true,"tier":"Advanced","description":"hosts"}`
+
+	req := httptest.NewRequest("POST", "/ssm", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	in := mock.putInput
+	if in.Type != types.ParameterTypeStringList || !aws.ToBool(in.Overwrite) || in.Tier != types.ParameterTierAdvanced {
+		t.Errorf("got input %+v", in)
+	}
+	if aws.ToString(in.Description) != "hosts" {
+		t.Errorf("got description %q want hosts", aws.ToString(in.Description))
+	}
+}
+
+func TestHandlePostSSM_Policies(t *testing.T) {
+	mock := &mockSSM{}
+	body := `{"name":"/ci/token","value":"t","type":"SecureString","policies":[
+		{"type":"Expiration","timestamp":"2026-12-01T10:00:00+02:00"},
+		{"type":"ExpirationNotification","before":2,"unit":"Hours"}]}`
+
+	req := httptest.NewRequest("POST", "/ssm", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	in := mock.putInput
+	if in.Tier != types.ParameterTierAdvanced {
+		t.Errorf("got tier %q want Advanced", in.Tier)
+	}
+	want := `[{"Type":"Expiration","Version":"1.0","Attributes":{"Timestamp":"2026-12-01T08:00:00.000Z"}},` +
+		`{"Type":"ExpirationNotification","Version":"1.0","Attributes":{"Before":"2","Unit":"Hours"}}]`
+	if aws.ToString(in.Policies) != want {
+		t.Errorf("got policies %s want %s", aws.ToString(in.Policies), want)
+	}
+}
+
+func TestHandlePostSSM_InvalidPolicies(t *testing.T) {
+	for _, body := range []string{
+		`{"name":"p","value":"v","type":"String","tier":"Standard","policies":[{"type":"NoChangeNotification","after":5}]}`,
+		`{"name":"p","value":"v","type":"String","policies":[{"type":"Expiration","timestamp":"tomorrow"}]}`,
+		`{"name":"p","value":"v","type":"String","policies":[{"type":"Unknown"}]}`,
+	} {
+		req := httptest.NewRequest("POST", "/ssm", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		HandleSSM(rr, req, &mockSSM{})
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d want %d", body, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandlePostSSM_FormTags(t *testing.T) {
+	mock := &mockSSM{}
+	form := url.Values{"name": {"p"}, "value": {"v"}, "type": {"SecureString"}, "key_id": {"alias/app"}, "tag": {"team=core"}}
+
+	req := httptest.NewRequest("POST", "/ssm", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	in := mock.putInput
+	if aws.ToString(in.KeyId) != "alias/app" || len(in.Tags) != 1 || aws.ToString(in.Tags[0].Value) != "core" {
+		t.Errorf("got input %+v", in)
+	}
+}
+
+func TestHandlePostSSM_TagsWithOverwrite(t *testing.T) {
+	form := url.Values{"name": {"p"}, "value": {"v"}, "type": {"String"}, "overwrite": {"true"}, "tag": {"team=core"}}
+
+	req := httptest.NewRequest("POST", "/ssm", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, &mockSSM{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandlePostSSM_AlreadyExists(t *testing.T) {
+	form := url.Values{"name": {"p"}, "value": {"v"}, "type": {"String"}}
+
+	req := httptest.NewRequest("POST", "/ssm", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, &mockSSM{err: &types.ParameterAlreadyExists{}})
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("got %d want %d", rr.Code, http.StatusConflict)
+	}
+}
+
+func TestHandleDeleteSSM(t *testing.T) {
+	mock := &mockSSM{}
+	req := httptest.NewRequest("DELETE", "/ssm?name=/app/key", nil)
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if len(mock.deleted) != 1 || mock.deleted[0] != "/app/key" {
+		t.Errorf("got deleted %v want [/app/key]", mock.deleted)
+	}
+}
+
+func TestHandleDeleteSSM_NotFound(t *testing.T) {
+	req := httptest.NewRequest("DELETE", "/ssm?name=nope", nil)
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, &mockSSM{err: &types.ParameterNotFound{}})
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleDeleteSSM_Path(t *testing.T) {
+	mock := &mockSSM{pathPages: map[string]ssm.GetParametersByPathOutput{
+		"": {Parameters: []types.Parameter{{Name: aws.String("/review/x/a")}, {Name: aws.String("/review/x/b")}}},
+	}}
+	req := httptest.NewRequest("DELETE", "/ssm?path=/review/x/&recursive=true&name=missing", nil)
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	var got deleteResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(got.DeletedParameters) != 2 || len(got.InvalidParameters) != 1 {
+		t.Errorf("got %+v want two deleted and one invalid", got)
+	}
+}
+
+func TestHandleDeleteSSM_MissingName(t *testing.T) {
 	req := httptest.NewRequest("DELETE", "/ssm", nil)
 	rr := httptest.NewRecorder()
 	HandleSSM(rr, req, &mockSSM{})
 
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleSSM_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest("PATCH", "/ssm", nil)
+	rr := httptest.NewRecorder()
+	HandleSSM(rr, req, &mockSSM{})
+
 	if rr.Code != http.StatusMethodNotAllowed {
 		t.Errorf("got %d want %d", rr.Code, http.StatusMethodNotAllowed)
 	}
 }
+
+func TestHandleSSMPath(t *testing.T) {
+	mock := &mockSSM{pathPages: map[string]ssm.GetParametersByPathOutput{
+		"": {
+			Parameters: []types.Parameter{{Name: aws.String("/app/db/user"), Value: aws.String("admin")}},
+			NextToken:  aws.String("page2"),
+		},
+		"page2": {
+			Parameters: []types.Parameter{{Name: aws.String("/app/db/pass"), Value: aws.String("secret")}},
+		},
+	}}
+
+	req := httptest.NewRequest("GET", "/ssm/path?path=/app/&recursive=true&strip_prefix=true", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMPath(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(got) != 2 || got["db/user"] != "admin" || got["db/pass"] != "secret" {
+		t.Errorf("got %v want both pages with prefix stripped", got)
+	}
+}
+
+func TestHandleSSMPath_MissingPath(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/path", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMPath(rr, req, &mockSSM{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleSSMBatch(t *testing.T) {
+	mock := &mockSSM{}
+	query := url.Values{}
+	for i := range 11 {
+		query.Add("name", fmt.Sprintf("p%d", i))
+	}
+	query.Add("name", "missing")
+
+	req := httptest.NewRequest("GET", "/ssm/batch?"+query.Encode(), nil)
+	rr := httptest.NewRecorder()
+	HandleSSMBatch(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if len(mock.batchCall) != 2 || len(mock.batchCall[0]) != 10 {
+		t.Errorf("got calls %v want chunks of 10", mock.batchCall)
+	}
+	var got batchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(got.Parameters) != 11 || got.Parameters["p3"] != "v-p3" {
+		t.Errorf("got parameters %v", got.Parameters)
+	}
+	if len(got.InvalidParameters) != 1 || got.InvalidParameters[0] != "missing" {
+		t.Errorf("got invalid %v want [missing]", got.InvalidParameters)
+	}
+}
+
+func TestHandleSSMBatch_JSONBody(t *testing.T) {
+	mock := &mockSSM{}
+	req := httptest.NewRequest("POST", "/ssm/batch", strings.NewReader(`{"names":["a","b"]}`))
+	rr := httptest.NewRecorder()
+	HandleSSMBatch(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if len(mock.batchCall) != 1 || len(mock.batchCall[0]) != 2 {
+		t.Errorf("got calls %v want one call with two names", mock.batchCall)
+	}
+}
+
+func TestHandleSSMBatch_NoNames(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/batch", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMBatch(rr, req, &mockSSM{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleSSMHistory(t *testing.T) {
+	mock := &mockSSM{history: []types.ParameterHistory{
+		{Version: 1, Value: aws.String("old"), Type: types.ParameterTypeString, LastModifiedUser: aws.String("alice")},
+		{Version: 2, Value: aws.String("new"), Type: types.ParameterTypeString, Labels: []string{"prod"}},
+	}}
+
+	req := httptest.NewRequest("GET", "/ssm/history?name=/app/key", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMHistory(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	var got []historyEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d entries want 2", len(got))
+	}
+	if got[0].Value != "old" || got[0].LastModifiedUser != "alice" {
+		t.Errorf("got first entry %+v", got[0])
+	}
+	if got[1].Version != 2 || len(got[1].Labels) != 1 || got[1].Labels[0] != "prod" {
+		t.Errorf("got second entry %+v", got[1])
+	}
+}
+
+func TestHandleSSMHistory_NotFound(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ssm/history?name=nope", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMHistory(rr, req, &mockSSM{err: &types.ParameterNotFound{}})
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got %d want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleSSMLabels(t *testing.T) {
+	mock := &mockSSM{}
+	body := `{"name":"/app/key","version":4,"labels":["prod"]}`
+
+	req := httptest.NewRequest("POST", "/ssm/labels", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	HandleSSMLabels(rr, req, mock)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if aws.ToInt64(mock.labelCall.ParameterVersion) != 4 || mock.labelCall.Labels[0] != "prod" {
+		t.Errorf("got input %+v", mock.labelCall)
+	}
+	if !strings.Contains(rr.Body.String(), `"parameter_version":4`) {
+		t.Errorf("got body %s", rr.Body.String())
+	}
+}
+
+func TestHandleSSMLabels_UnlabelRequiresVersion(t *testing.T) {
+	req := httptest.NewRequest("DELETE", "/ssm/labels?name=/app/key&label=prod", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMLabels(rr, req, &mockSSM{})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleSSMLabels_Unlabel(t *testing.T) {
+	req := httptest.NewRequest("DELETE", "/ssm/labels?name=/app/key&label=prod&version=2", nil)
+	rr := httptest.NewRecorder()
+	HandleSSMLabels(rr, req, &mockSSM{})
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d want %d", rr.Code, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), `"removed_labels":["prod"]`) {
+		t.Errorf("got body %s", rr.Body.String())
+	}
+}